@@ -1,11 +1,13 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -25,39 +27,200 @@ func (r *Range) Disjoint(other *Range) bool {
 	return r.Max < other.Min || r.Min > other.Max
 }
 
-func parse(line string) (*Range, *Range) {
-	pat := regexp.MustCompile(`([0-9]+)-([0-9]+),([0-9]+)-([0-9]+)`)
-	all := pat.FindStringSubmatch(line)
-	toint := func(s string) int {
-		n, _ := strconv.Atoi(s)
-		return n
+// Sections is the work assigned to one elf: one or more ranges, kept sorted
+// with any that touch or overlap merged together.
+type Sections []Range
+
+func NewSections(ranges ...Range) Sections {
+	s := append(Sections(nil), ranges...)
+	sort.Slice(s, func(i, j int) bool { return s[i].Min < s[j].Min })
+	var merged Sections
+	for _, r := range s {
+		if n := len(merged); n > 0 && r.Min <= merged[n-1].Max+1 {
+			if r.Max > merged[n-1].Max {
+				merged[n-1].Max = r.Max
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Union returns the sections covered by any of the groups.
+func Union(all ...Sections) Sections {
+	var ranges []Range
+	for _, s := range all {
+		ranges = append(ranges, s...)
+	}
+	return NewSections(ranges...)
+}
+
+// Because the ranges are merged, a range is covered by s exactly when it's
+// inside one of s's ranges.
+func (s Sections) Contains(other Sections) bool {
+	for i := range other {
+		inside := false
+		for j := range s {
+			if s[j].Contains(&other[i]) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Sections) Overlaps(other Sections) bool {
+	for i, j := 0, 0; i < len(s) && j < len(other); {
+		if !s[i].Disjoint(&other[j]) {
+			return true
+		}
+		if s[i].Max < other[j].Max {
+			i++
+		} else {
+			j++
+		}
+	}
+	return false
+}
+
+func (s Sections) String() string {
+	var parts []string
+	for _, r := range s {
+		parts = append(parts, fmt.Sprintf("%d-%d", r.Min, r.Max))
+	}
+	return strings.Join(parts, "+")
+}
+
+// Group is the elves listed on one line.
+type Group []Sections
+
+// AnyContains is true if some elf's work is entirely inside another's.
+func (g Group) AnyContains() bool {
+	for i := range g {
+		for j := range g {
+			if i != j && g[i].Contains(g[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AnyOverlap is true if any two elves share a section.
+func (g Group) AnyOverlap() bool {
+	for i := range g {
+		for j := i + 1; j < len(g); j++ {
+			if g[i].Overlaps(g[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CoveredByOthers returns the elves whose work is all covered by the rest of
+// the group put together, and so who could be sent home.
+func (g Group) CoveredByOthers() []int {
+	var covered []int
+	for i := range g {
+		others := make([]Sections, 0, len(g)-1)
+		others = append(others, g[:i]...)
+		others = append(others, g[i+1:]...)
+		if Union(others...).Contains(g[i]) {
+			covered = append(covered, i)
+		}
+	}
+	return covered
+}
+
+var rangepat = regexp.MustCompile(`^([0-9]+)-([0-9]+)$`)
+
+// parse reads a line of comma-separated assignments, one per elf, where each
+// assignment is one or more ranges joined with +, as in "2-4+7-9,3-8".
+func parse(line string) (Group, error) {
+	var g Group
+	for _, elf := range strings.Split(strings.TrimSpace(line), ",") {
+		var ranges []Range
+		for _, seg := range strings.Split(elf, "+") {
+			m := rangepat.FindStringSubmatch(strings.TrimSpace(seg))
+			if m == nil {
+				return nil, fmt.Errorf("bad range %q in %q", seg, line)
+			}
+			lo, _ := strconv.Atoi(m[1])
+			hi, _ := strconv.Atoi(m[2])
+			if lo > hi {
+				return nil, fmt.Errorf("backwards range %q in %q", seg, line)
+			}
+			ranges = append(ranges, Range{Min: lo, Max: hi})
+		}
+		g = append(g, NewSections(ranges...))
+	}
+	return g, nil
+}
+
+// calls f for the group on each nonblank line
+func eachGroup(lines []string, f func(lineNo int, g Group)) {
+	for i, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		g, err := parse(l)
+		if err != nil {
+			log.Fatalf("line %d: %v", i+1, err)
+		}
+		f(i+1, g)
 	}
-	return &Range{Min: toint(all[1]), Max: toint(all[2])}, &Range{Min: toint(all[3]), Max: toint(all[4])}
 }
 
 func part1(lines []string) {
 	containsCount := 0
-	for _, l := range lines {
-		r1, r2 := parse(l)
-		if r1.Contains(r2) || r2.Contains(r1) {
+	eachGroup(lines, func(_ int, g Group) {
+		if g.AnyContains() {
 			containsCount++
 		}
-	}
+	})
 	fmt.Println(containsCount)
 }
 
 func part2(lines []string) {
 	overlapCount := 0
-	for _, l := range lines {
-		r1, r2 := parse(l)
-		if !r1.Disjoint(r2) {
+	eachGroup(lines, func(_ int, g Group) {
+		if g.AnyOverlap() {
 			overlapCount++
 		}
-	}
+	})
 	fmt.Println(overlapCount)
 }
 
+// counts the lines where at least one elf isn't needed
+func covered(lines []string, verbose bool) {
+	count := 0
+	eachGroup(lines, func(lineNo int, g Group) {
+		idle := g.CoveredByOthers()
+		if len(idle) == 0 {
+			return
+		}
+		count++
+		if verbose {
+			for _, i := range idle {
+				fmt.Printf("line %d: elf %d (%v) is covered by the others\n", lineNo, i+1, g[i])
+			}
+		}
+	})
+	fmt.Println(count)
+}
+
 func main() {
+	analyze := flag.Bool("analyze", false, "analyze all the assignments together instead of solving")
+	groups := flag.Bool("covered", false, "count the lines where some elf's work is covered by the rest of the group")
+	verbose := flag.Bool("v", false, "with -analyze or -covered, list the details")
+	flag.Parse()
+
 	f, err := os.Open("./input.txt")
 	if err != nil {
 		log.Fatal(err)
@@ -67,6 +230,14 @@ func main() {
 		log.Fatal(err)
 	}
 	lines := strings.Split(string(b), "\n")
+	if *analyze {
+		Analyze(assignments(lines), *verbose).Print(*verbose)
+		return
+	}
+	if *groups {
+		covered(lines, *verbose)
+		return
+	}
 	part1(lines)
 	part2(lines)
 }
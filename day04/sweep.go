@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Assignment is one range of one elf's sections, remembering where it came from.
+type Assignment struct {
+	Line  int // 1-based line number in the input
+	Elf   int // position on the line, from 0
+	Range *Range
+}
+
+func (a Assignment) String() string {
+	return fmt.Sprintf("line %d elf %d (%d-%d)", a.Line, a.Elf+1, a.Range.Min, a.Range.Max)
+}
+
+// orders assignments as they appear in the file
+func before(a, b Assignment) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Elf < b.Elf)
+}
+
+type Pair struct {
+	A Assignment
+	B Assignment
+}
+
+// Containment records that Inner is entirely covered by Outer.
+type Containment struct {
+	Inner Assignment
+	Outer Assignment
+}
+
+type Analysis struct {
+	Assignments     int
+	OverlapCount    int           // number of overlapping pairs
+	Overlaps        []Pair        // only filled in if asked for
+	Redundant       []Containment // assignments entirely inside another one (see redundant)
+	MaxCoverage     int           // most assignments covering any one section
+	MaxCoverageAt   Range         // the first run of sections with that coverage
+	Uncovered       []Range       // sections from 1 to the highest assigned that nobody has
+	HighestAssigned int
+}
+
+// Each range of each elf's work becomes a separate assignment; an elf with
+// work in two places has two.
+func assignments(lines []string) []Assignment {
+	var all []Assignment
+	eachGroup(lines, func(lineNo int, g Group) {
+		for elf, sections := range g {
+			for i := range sections {
+				all = append(all, Assignment{Line: lineNo, Elf: elf, Range: &sections[i]})
+			}
+		}
+	})
+	return all
+}
+
+type event struct {
+	at    int // section where the change happens
+	delta int // +1 for a start, -1 for the section after an end
+	ix    int
+}
+
+// Analyze looks at every assignment in the file at once, rather than just the
+// pairs on each line. Everything except listing the overlaps themselves is done
+// with a single sort and sweep, so it's O(n log n); listing the overlaps adds
+// time proportional to the number of them.
+func Analyze(all []Assignment, listOverlaps bool) *Analysis {
+	an := &Analysis{Assignments: len(all)}
+	if len(all) == 0 {
+		return an
+	}
+
+	events := make([]event, 0, 2*len(all))
+	for i, a := range all {
+		events = append(events, event{at: a.Range.Min, delta: 1, ix: i}, event{at: a.Range.Max + 1, delta: -1, ix: i})
+	}
+	// at the same section, ends go first: a range ending at 5 and one starting
+	// at 6 don't overlap
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at != events[j].at {
+			return events[i].at < events[j].at
+		}
+		return events[i].delta < events[j].delta
+	})
+
+	active := make(map[int]struct{})
+	coverage := 0
+	covered := 1 // first section not yet known to be covered or uncovered
+	inMaxRun := false
+	for i, e := range events {
+		if e.delta > 0 {
+			an.OverlapCount += coverage
+			if listOverlaps {
+				for other := range active {
+					a, b := other, e.ix
+					if b < a {
+						a, b = b, a
+					}
+					an.Overlaps = append(an.Overlaps, Pair{A: all[a], B: all[b]})
+				}
+				active[e.ix] = struct{}{}
+			}
+			if coverage == 0 && e.at > covered {
+				an.Uncovered = append(an.Uncovered, Range{Min: covered, Max: e.at - 1})
+			}
+		} else {
+			delete(active, e.ix)
+		}
+		coverage += e.delta
+		if coverage == 0 {
+			covered = e.at
+		}
+		// coverage holds from here up to the next event; a run at the maximum
+		// can carry on past an event where one range ends and another starts
+		if i+1 < len(events) && events[i+1].at > e.at {
+			span := Range{Min: e.at, Max: events[i+1].at - 1}
+			switch {
+			case coverage > an.MaxCoverage:
+				an.MaxCoverage = coverage
+				an.MaxCoverageAt = span
+				inMaxRun = true
+			case coverage == an.MaxCoverage && inMaxRun:
+				an.MaxCoverageAt.Max = span.Max
+			default:
+				inMaxRun = false
+			}
+		}
+	}
+	an.HighestAssigned = events[len(events)-1].at - 1
+	if listOverlaps {
+		sort.Slice(an.Overlaps, func(i, j int) bool {
+			a, b := an.Overlaps[i], an.Overlaps[j]
+			if a.A != b.A {
+				return before(a.A, b.A)
+			}
+			return before(a.B, b.B)
+		})
+	}
+	an.Redundant = redundant(all)
+	return an
+}
+
+// Finds every assignment that is covered by some other single assignment.
+// Sorting by start, with longer ranges first on ties, means that a range is
+// covered exactly when some range before it reaches at least as far.
+//
+// Identical ranges cover each other, so every one of them is reported as
+// redundant, each inside another. That means dropping all the redundant
+// assignments can leave sections uncovered: of two elves with 3-5, at least
+// one has to stay.
+func redundant(all []Assignment) []Containment {
+	order := make([]int, len(all))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := all[order[i]].Range, all[order[j]].Range
+		if a.Min != b.Min {
+			return a.Min < b.Min
+		}
+		return a.Max > b.Max
+	})
+
+	var found []Containment
+	widest := -1 // the range seen so far that reaches furthest
+	for n, ix := range order {
+		r := all[ix].Range
+		switch {
+		case widest >= 0 && all[widest].Range.Contains(r):
+			found = append(found, Containment{Inner: all[ix], Outer: all[widest]})
+		case n+1 < len(order) && *all[order[n+1]].Range == *r:
+			// the first of a run of identical ranges is covered by the next one
+			found = append(found, Containment{Inner: all[ix], Outer: all[order[n+1]]})
+		}
+		if widest < 0 || r.Max > all[widest].Range.Max {
+			widest = ix
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return before(found[i].Inner, found[j].Inner)
+	})
+	return found
+}
+
+func (an *Analysis) Print(verbose bool) {
+	fmt.Printf("%d assignments covering sections 1-%d\n", an.Assignments, an.HighestAssigned)
+	fmt.Printf("%d overlapping pairs\n", an.OverlapCount)
+	for _, p := range an.Overlaps {
+		fmt.Printf("  %v overlaps %v\n", p.A, p.B)
+	}
+	fmt.Printf("%d redundant assignments\n", len(an.Redundant))
+	if verbose {
+		for _, c := range an.Redundant {
+			fmt.Printf("  %v is inside %v\n", c.Inner, c.Outer)
+		}
+	}
+	fmt.Printf("max coverage %d, at sections %d-%d\n", an.MaxCoverage, an.MaxCoverageAt.Min, an.MaxCoverageAt.Max)
+	fmt.Printf("uncovered sections: %v\n", an.Uncovered)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func randomAssignments(r *rand.Rand) []Assignment {
+	var all []Assignment
+	lines := 1 + r.Intn(15)
+	for line := 1; line <= lines; line++ {
+		elves := 1 + r.Intn(3)
+		for elf := 0; elf < elves; elf++ {
+			lo := 1 + r.Intn(25)
+			hi := lo + r.Intn(8)
+			all = append(all, Assignment{Line: line, Elf: elf, Range: &Range{Min: lo, Max: hi}})
+		}
+	}
+	return all
+}
+
+type bruteResult struct {
+	overlaps      []string
+	redundant     []string
+	maxCoverage   int
+	maxCoverageAt Range
+	uncovered     []Range
+	highest       int
+}
+
+// works everything out by comparing every pair and counting every section
+func bruteForce(all []Assignment) bruteResult {
+	var br bruteResult
+	for i := range all {
+		inside := false
+		for j := range all {
+			if i == j {
+				continue
+			}
+			if i < j && !all[i].Range.Disjoint(all[j].Range) {
+				br.overlaps = append(br.overlaps, fmt.Sprint(all[i], all[j]))
+			}
+			if all[j].Range.Contains(all[i].Range) {
+				inside = true
+			}
+		}
+		if inside {
+			br.redundant = append(br.redundant, all[i].String())
+		}
+		if all[i].Range.Max > br.highest {
+			br.highest = all[i].Range.Max
+		}
+	}
+	sort.Strings(br.overlaps)
+
+	count := make([]int, br.highest+2)
+	for _, a := range all {
+		for s := a.Range.Min; s <= a.Range.Max; s++ {
+			count[s]++
+		}
+	}
+	for s := 1; s <= br.highest; s++ {
+		if count[s] > br.maxCoverage {
+			br.maxCoverage = count[s]
+			br.maxCoverageAt = Range{Min: s, Max: s}
+			for s+1 <= br.highest && count[s+1] == br.maxCoverage {
+				s++
+				br.maxCoverageAt.Max = s
+			}
+		}
+		if count[s] == 0 {
+			if n := len(br.uncovered); n > 0 && br.uncovered[n-1].Max == s-1 {
+				br.uncovered[n-1].Max = s
+			} else {
+				br.uncovered = append(br.uncovered, Range{Min: s, Max: s})
+			}
+		}
+	}
+	return br
+}
+
+func TestAnalyzeBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 500; trial++ {
+		all := randomAssignments(r)
+		want := bruteForce(all)
+		an := Analyze(all, true)
+
+		if an.OverlapCount != len(want.overlaps) {
+			t.Fatalf("trial %d: %d overlaps, want %d", trial, an.OverlapCount, len(want.overlaps))
+		}
+		var overlaps []string
+		for _, p := range an.Overlaps {
+			overlaps = append(overlaps, fmt.Sprint(p.A, p.B))
+		}
+		sort.Strings(overlaps)
+		if !reflect.DeepEqual(overlaps, want.overlaps) {
+			t.Fatalf("trial %d: overlaps\n%v\nwant\n%v", trial, overlaps, want.overlaps)
+		}
+
+		var redundant []string
+		for _, c := range an.Redundant {
+			if c.Inner == c.Outer || !c.Outer.Range.Contains(c.Inner.Range) {
+				t.Fatalf("trial %d: %v isn't inside %v", trial, c.Inner, c.Outer)
+			}
+			redundant = append(redundant, c.Inner.String())
+		}
+		sort.Strings(redundant)
+		sort.Strings(want.redundant)
+		if !reflect.DeepEqual(redundant, want.redundant) {
+			t.Fatalf("trial %d: redundant\n%v\nwant\n%v", trial, redundant, want.redundant)
+		}
+
+		if an.MaxCoverage != want.maxCoverage || an.MaxCoverageAt != want.maxCoverageAt {
+			t.Fatalf("trial %d: max coverage %d at %v, want %d at %v",
+				trial, an.MaxCoverage, an.MaxCoverageAt, want.maxCoverage, want.maxCoverageAt)
+		}
+		if !reflect.DeepEqual(an.Uncovered, want.uncovered) {
+			t.Fatalf("trial %d: uncovered %v, want %v", trial, an.Uncovered, want.uncovered)
+		}
+		if an.HighestAssigned != want.highest {
+			t.Fatalf("trial %d: highest %d, want %d", trial, an.HighestAssigned, want.highest)
+		}
+
+		// counting without listing gives the same count
+		if n := Analyze(all, false).OverlapCount; n != an.OverlapCount {
+			t.Fatalf("trial %d: %d overlaps without the list, %d with", trial, n, an.OverlapCount)
+		}
+	}
+}
+
+func TestIdenticalRangesAreBothRedundant(t *testing.T) {
+	all := []Assignment{
+		{Line: 1, Elf: 0, Range: &Range{Min: 3, Max: 5}},
+		{Line: 1, Elf: 1, Range: &Range{Min: 3, Max: 5}},
+		{Line: 2, Elf: 0, Range: &Range{Min: 8, Max: 9}},
+	}
+	an := Analyze(all, false)
+	if len(an.Redundant) != 2 {
+		t.Fatalf("redundant: %v", an.Redundant)
+	}
+	for _, c := range an.Redundant {
+		if c.Inner.Line != 1 || c.Outer.Line != 1 || c.Inner.Elf == c.Outer.Elf {
+			t.Errorf("%v inside %v", c.Inner, c.Outer)
+		}
+	}
+	if !reflect.DeepEqual(an.Uncovered, []Range{{1, 2}, {6, 7}}) {
+		t.Errorf("uncovered %v", an.Uncovered)
+	}
+}
+
+func TestMaxCoverageRunAcrossEvents(t *testing.T) {
+	// coverage is 1 all the way from 1 to 9, across the end of one range and
+	// the start of the next
+	all := []Assignment{
+		{Line: 1, Elf: 0, Range: &Range{Min: 1, Max: 5}},
+		{Line: 1, Elf: 1, Range: &Range{Min: 6, Max: 9}},
+	}
+	an := Analyze(all, false)
+	if an.MaxCoverage != 1 || an.MaxCoverageAt != (Range{1, 9}) {
+		t.Errorf("max coverage %d at %v", an.MaxCoverage, an.MaxCoverageAt)
+	}
+}
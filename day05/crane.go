@@ -0,0 +1,117 @@
+package main
+
+import "fmt"
+
+// Crane is a model of crane. Each model carries out a move in its own way.
+type Crane interface {
+	Name() string
+	Move(c *Cargo, cmd Command)
+}
+
+var registry []func() Crane
+
+// Register adds a crane model to the list that RegisteredCranes returns. The
+// function is called to make a fresh crane for each run, so cranes can keep
+// state between moves.
+func Register(f func() Crane) {
+	registry = append(registry, f)
+}
+
+func RegisteredCranes() []Crane {
+	var cranes []Crane
+	for _, f := range registry {
+		cranes = append(cranes, f())
+	}
+	return cranes
+}
+
+func init() {
+	Register(func() Crane { return CrateMover9000{} })
+	Register(func() Crane { return CrateMover9001{} })
+	Register(func() Crane { return ChunkCrane{Capacity: 2} })
+	Register(func() Crane { return ChunkCrane{Capacity: 3} })
+	Register(func() Crane { return BottomCrane{} })
+	Register(func() Crane { return &AlternatingCrane{} })
+}
+
+// CrateMover9000 moves crates one at a time (part 1).
+type CrateMover9000 struct{}
+
+func (CrateMover9000) Name() string { return "CrateMover 9000" }
+
+func (CrateMover9000) Move(c *Cargo, cmd Command) {
+	c.MoveSingle(cmd)
+}
+
+// CrateMover9001 moves all the crates at once, keeping their order (part 2).
+type CrateMover9001 struct{}
+
+func (CrateMover9001) Name() string { return "CrateMover 9001" }
+
+func (CrateMover9001) Move(c *Cargo, cmd Command) {
+	c.MoveMulti(cmd)
+}
+
+// ChunkCrane can only lift Capacity crates at a time, so it moves a big
+// order in several lifts, each of which keeps its crates in order. Capacity
+// must be at least 1.
+type ChunkCrane struct {
+	Capacity int
+}
+
+func (cc ChunkCrane) Name() string { return fmt.Sprintf("chunking crane (capacity %d)", cc.Capacity) }
+
+func (cc ChunkCrane) Move(c *Cargo, cmd Command) {
+	if cc.Capacity < 1 {
+		panic(fmt.Sprintf("ChunkCrane capacity must be at least 1, not %d", cc.Capacity))
+	}
+	for left := cmd.Qty; left > 0; left -= cc.Capacity {
+		n := cc.Capacity
+		if left < n {
+			n = left
+		}
+		if avail := c.stacks[cmd.FrIx].Len(); avail < n {
+			n = avail
+		}
+		if n == 0 {
+			return
+		}
+		c.MoveMulti(Command{Qty: n, FrIx: cmd.FrIx, ToIx: cmd.ToIx})
+	}
+}
+
+// BottomCrane lifts the crates all at once and slides them in underneath the
+// destination stack.
+type BottomCrane struct{}
+
+func (BottomCrane) Name() string { return "bottom-inserting crane" }
+
+func (BottomCrane) Move(c *Cargo, cmd Command) {
+	if crates, ok := c.stacks[cmd.FrIx].PopN(cmd.Qty); ok {
+		c.stacks[cmd.ToIx].InsertBottom(crates)
+	}
+}
+
+// AlternatingCrane moves all the crates at once, but every other lift comes
+// out upside down.
+type AlternatingCrane struct {
+	lifts int
+}
+
+func (ac *AlternatingCrane) Name() string { return "alternating crane" }
+
+func (ac *AlternatingCrane) Move(c *Cargo, cmd Command) {
+	crates, ok := c.stacks[cmd.FrIx].PopN(cmd.Qty)
+	if !ok {
+		return
+	}
+	if ac.lifts%2 == 1 {
+		flipped := make([]string, len(crates))
+		for i, crate := range crates {
+			flipped[len(crates)-1-i] = crate
+		}
+		crates = flipped
+	}
+	ac.lifts++
+	c.stacks[cmd.ToIx].PushN(crates)
+}
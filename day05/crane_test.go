@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+// three stacks, bottom first: [A B C D E], [F], []
+func fiveCrates(commands ...Command) *Cargo {
+	return NewCargo([][]string{{"A", "B", "C", "D", "E"}, {"F"}, {}}, commands)
+}
+
+func TestCranes(t *testing.T) {
+	move4 := Command{Qty: 4, FrIx: 0, ToIx: 2}
+	move2 := Command{Qty: 2, FrIx: 2, ToIx: 1}
+	tests := []struct {
+		crane Crane
+		cmds  []Command
+		want  string
+	}{
+		{CrateMover9000{}, []Command{move4}, "A F E|D|C|B"},
+		{CrateMover9001{}, []Command{move4}, "A F B|C|D|E"},
+		// lifts D E, then B C
+		{ChunkCrane{Capacity: 2}, []Command{move4}, "A F D|E|B|C"},
+		// lifts C D E, then B
+		{ChunkCrane{Capacity: 3}, []Command{move4}, "A F C|D|E|B"},
+		{ChunkCrane{Capacity: 1}, []Command{move4}, "A F E|D|C|B"},
+		{ChunkCrane{Capacity: 10}, []Command{move4}, "A F B|C|D|E"},
+		// asks for more than there is, so takes what it can
+		{ChunkCrane{Capacity: 2}, []Command{{Qty: 9, FrIx: 1, ToIx: 2}}, "A|B|C|D|E  F"},
+		{BottomCrane{}, []Command{{Qty: 2, FrIx: 0, ToIx: 1}}, "A|B|C D|E|F "},
+		{BottomCrane{}, []Command{move4, move2}, "A D|E|F B|C"},
+		// the first lift keeps its order, the second is flipped
+		{&AlternatingCrane{}, []Command{move4, move2}, "A F|E|D B|C"},
+		{&AlternatingCrane{}, []Command{{Qty: 2, FrIx: 0, ToIx: 2}, {Qty: 2, FrIx: 0, ToIx: 2}}, "A F D|E|C|B"},
+	}
+	for _, tt := range tests {
+		c := fiveCrates(tt.cmds...)
+		c.Exec(tt.crane)
+		if got := stacks(c); got != tt.want {
+			t.Errorf("%s after %v: %q, want %q", tt.crane.Name(), tt.cmds, got, tt.want)
+		}
+	}
+}
+
+func TestRegisteredCranesAreFresh(t *testing.T) {
+	// the alternating crane counts its lifts, so each run needs a new one
+	for run := 0; run < 2; run++ {
+		for _, crane := range RegisteredCranes() {
+			if _, ok := crane.(*AlternatingCrane); !ok {
+				continue
+			}
+			c := fiveCrates(Command{Qty: 2, FrIx: 0, ToIx: 2})
+			c.Exec(crane)
+			if got := stacks(c); got != "A|B|C F D|E" {
+				t.Errorf("run %d: %q", run, got)
+			}
+		}
+	}
+}
+
+func TestChunkCraneCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("capacity %d didn't panic", capacity)
+				}
+			}()
+			fiveCrates(Command{Qty: 2, FrIx: 0, ToIx: 2}).Exec(ChunkCrane{Capacity: capacity})
+		}()
+	}
+	var s Stack
+	s.PushN([]string{"A"})
+	if _, ok := s.PopN(-1); ok || s.Len() != 1 {
+		t.Error("PopN(-1) succeeded")
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NewCargo makes cargo from a list of stacks, each listed bottom crate first,
+// and the commands to run on them. The stacks are labelled from 1.
+func NewCargo(stacks [][]string, commands []Command) *Cargo {
+	c := &Cargo{
+		stacks:   make([]Stack, len(stacks)),
+		commands: append([]Command(nil), commands...),
+	}
+	for i, s := range stacks {
+		c.stacks[i].PushN(s)
+		c.labels = append(c.labels, i+1)
+	}
+	return c
+}
+
+// column widths for drawing: wide enough for the biggest crate in the stack
+// and for its label
+func (c *Cargo) widths() []int {
+	widths := make([]int, len(c.stacks))
+	for i, s := range c.stacks {
+		w := len(strconv.Itoa(c.label(i)))
+		for _, crate := range s.s {
+			if len(crate)+2 > w {
+				w = len(crate) + 2
+			}
+		}
+		if w < 3 {
+			w = 3
+		}
+		widths[i] = w
+	}
+	return widths
+}
+
+// label returns the number drawn under stack i; cargo that wasn't parsed is
+// numbered from 1.
+func (c *Cargo) label(i int) int {
+	if i < len(c.labels) {
+		return c.labels[i]
+	}
+	return i + 1
+}
+
+// Drawing renders the stacks the way the puzzle draws them: crates in
+// brackets, one column per stack, with the row of stack labels underneath.
+func (c *Cargo) Drawing() string {
+	widths := c.widths()
+	height := 0
+	for _, s := range c.stacks {
+		if s.Len() > height {
+			height = s.Len()
+		}
+	}
+
+	sb := strings.Builder{}
+	for row := height - 1; row >= 0; row-- {
+		cols := make([]string, len(c.stacks))
+		for i, s := range c.stacks {
+			cell := ""
+			if row < s.Len() {
+				cell = "[" + s.s[row] + "]"
+			}
+			cols[i] = strings.Repeat(" ", widths[i]-len(cell)) + cell
+		}
+		sb.WriteString(strings.TrimRight(strings.Join(cols, " "), " "))
+		sb.WriteString("\n")
+	}
+
+	// each label sits under the middle of a 3-wide crate, which puts one space
+	// after it
+	cols := make([]string, len(c.stacks))
+	for i, w := range widths {
+		l := strconv.Itoa(c.label(i))
+		pad := w - 1 - len(l)
+		if pad < 0 {
+			pad = 0
+		}
+		cols[i] = strings.Repeat(" ", pad) + l + strings.Repeat(" ", w-pad-len(l))
+	}
+	sb.WriteString(strings.TrimRight(strings.Join(cols, " "), " "))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// Moves renders the commands in the puzzle's format, one per line.
+func (c *Cargo) Moves() string {
+	sb := strings.Builder{}
+	for _, cmd := range c.commands {
+		fmt.Fprintf(&sb, "move %d from %d to %d\n", cmd.Qty, c.label(cmd.FrIx), c.label(cmd.ToIx))
+	}
+	return sb.String()
+}
+
+// String renders the cargo as a complete puzzle input, which Parse reads back
+// into the same cargo.
+func (c *Cargo) String() string {
+	return c.Drawing() + "\n" + c.Moves()
+}
+
+func (c *Cargo) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, c.String())
+	return int64(n), err
+}
+
+// Equal reports whether two cargos have the same labelled stacks and the same
+// commands.
+func (c *Cargo) Equal(other *Cargo) bool {
+	if len(c.stacks) != len(other.stacks) || len(c.commands) != len(other.commands) {
+		return false
+	}
+	for i := range c.stacks {
+		if c.label(i) != other.label(i) || c.stacks[i].String() != other.stacks[i].String() {
+			return false
+		}
+	}
+	for i := range c.commands {
+		if c.commands[i] != other.commands[i] {
+			return false
+		}
+	}
+	return true
+}
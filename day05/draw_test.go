@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// parses s, writes it out and parses that, checking the cargo survives
+func roundTrip(t *testing.T, s string) *Cargo {
+	t.Helper()
+	cargo, err := Parse(strings.Split(s, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := cargo.String()
+	again, err := Parse(strings.Split(out, "\n"))
+	if err != nil {
+		t.Fatalf("can't parse what was written: %v\n%s", err, out)
+	}
+	if !cargo.Equal(again) {
+		t.Fatalf("round trip changed the cargo:\n%s", out)
+	}
+	if again.String() != out {
+		t.Fatalf("writing twice gave different results")
+	}
+	return cargo
+}
+
+func TestRoundTripFiles(t *testing.T) {
+	for _, name := range []string{"inputsample.txt", "input.txt"} {
+		t.Run(name, func(t *testing.T) {
+			b, err := os.ReadFile(name)
+			if err != nil {
+				t.Skip(err)
+			}
+			cargo := roundTrip(t, string(b))
+			// the puzzle files are already in the canonical form
+			if got := strings.TrimSuffix(cargo.String(), "\n"); got != strings.TrimSuffix(string(b), "\n") {
+				t.Errorf("written puzzle differs from %s:\n%s", name, got)
+			}
+		})
+	}
+}
+
+func TestRoundTripGenerated(t *testing.T) {
+	stacks := make([][]string, 12)
+	for i := range stacks {
+		for j := 0; j < (i*7)%5; j++ {
+			stacks[i] = append(stacks[i], fmt.Sprintf("%c%d", 'A'+i, j*j*3))
+		}
+	}
+	commands := []Command{
+		{Qty: 1, FrIx: 1, ToIx: 11},
+		{Qty: 2, FrIx: 10, ToIx: 0},
+		{Qty: 3, FrIx: 8, ToIx: 9},
+	}
+	cargo := NewCargo(stacks, commands)
+	s := cargo.String()
+	lines := strings.Split(s, "\n")
+	labels := strings.Fields(lines[4])
+	if len(labels) != 12 || labels[0] != "1" || labels[11] != "12" || lines[7] != "move 2 from 11 to 1" {
+		t.Errorf("unexpected drawing:\n%s", s)
+	}
+	again := roundTrip(t, s)
+	if !cargo.Equal(again) {
+		t.Errorf("parsed cargo differs from the generated one:\n%s", s)
+	}
+}
+
+func TestPuzzleFromReplay(t *testing.T) {
+	b, err := os.ReadFile("inputsample.txt")
+	if err != nil {
+		t.Skip(err)
+	}
+	cargo, err := Parse(strings.Split(string(b), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, crane := range []Crane{CrateMover9000{}, CrateMover9001{}} {
+		whole := cargo.Clone()
+		whole.Exec(crane)
+		for n := 0; n <= len(cargo.commands); n++ {
+			r := NewReplay(cargo, crane)
+			if err := r.Jump(n); err != nil {
+				t.Fatal(err)
+			}
+			written := r.Puzzle().String()
+			puzzle, err := Parse(strings.Split(written, "\n"))
+			if err != nil {
+				t.Fatalf("%s after %d: %v\n%s", crane.Name(), n, err, written)
+			}
+			if len(puzzle.commands) != len(cargo.commands)-n {
+				t.Errorf("%s after %d: %d commands left", crane.Name(), n, len(puzzle.commands))
+			}
+			puzzle.Exec(crane)
+			if puzzle.Tops() != whole.Tops() {
+				t.Errorf("%s after %d: puzzle solves to %s, want %s", crane.Name(), n, puzzle.Tops(), whole.Tops())
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// the second command asks stack 1 for 3 crates when it only has 1
+func overdrawn() *Cargo {
+	return NewCargo([][]string{{"A", "B"}, {"C"}, {}}, []Command{
+		{Qty: 1, FrIx: 0, ToIx: 2},
+		{Qty: 3, FrIx: 0, ToIx: 1},
+		{Qty: 1, FrIx: 1, ToIx: 0},
+	})
+}
+
+func stacks(c *Cargo) string {
+	var s []string
+	for _, st := range c.stacks {
+		s = append(s, st.String())
+	}
+	return strings.Join(s, " ")
+}
+
+func TestExecStrict(t *testing.T) {
+	for _, crane := range []Crane{CrateMover9000{}, CrateMover9001{}} {
+		c := overdrawn()
+		err := c.ExecStrict(crane)
+		if err == nil {
+			t.Fatalf("%s: no error", crane.Name())
+		}
+		want := "command 2 (move 3 from 1 to 2): stack 1 has 1 crates and stack 2 has 1"
+		if err.Error() != want {
+			t.Errorf("%s: error %q, want %q", crane.Name(), err, want)
+		}
+		// only the first command has run
+		if got := stacks(c); got != "A C B" {
+			t.Errorf("%s: stacks are %q, want %q", crane.Name(), got, "A C B")
+		}
+	}
+	c := NewCargo([][]string{{"A", "B"}}, []Command{{Qty: 2, FrIx: 0, ToIx: 0}})
+	if err := c.ExecStrict(CrateMover9001{}); err != nil {
+		t.Errorf("possible move gave %v", err)
+	}
+}
+
+func TestExecLenient(t *testing.T) {
+	tests := []struct {
+		crane  Crane
+		log    string
+		stacks string
+	}{
+		{CrateMover9000{}, "command 2: asked for 3 crates, stack had 1, moved only 1", "A C B"},
+		{CrateMover9001{}, "command 2: asked for 3 crates, stack had 1, skipped", "A|C  B"},
+	}
+	for _, tt := range tests {
+		c := overdrawn()
+		short := c.ExecLenient(tt.crane)
+		if len(short) != 1 {
+			t.Fatalf("%s: %d shortfalls, want 1: %v", tt.crane.Name(), len(short), short)
+		}
+		if short[0].Index != 2 || short[0].Had != 1 || short[0].String() != tt.log {
+			t.Errorf("%s: shortfall %+v %q, want %q", tt.crane.Name(), short[0], short[0], tt.log)
+		}
+		if got := stacks(c); got != tt.stacks {
+			t.Errorf("%s: stacks are %q, want %q", tt.crane.Name(), got, tt.stacks)
+		}
+	}
+}
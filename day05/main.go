@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -18,52 +20,104 @@ type Command struct {
 
 type Cargo struct {
 	stacks   []Stack
+	labels   []int // the number under each stack in the drawing
 	commands []Command
 }
 
-func Parse(lines []string) *Cargo {
-	// we're gonna use a Stack to parse the cargo
-	// because we need to iterate it in reverse
-	items := NewStack()
-	var nstacks int
-	var ix int
-	for _, l := range lines {
-		ix++
-		if !strings.HasPrefix(strings.TrimSpace(l), "[") {
-			// we have a line of numbers here, grab the last one
-			nstacks = int(l[len(l)-1] - '0')
+var (
+	labelpat = regexp.MustCompile("[0-9]+")
+	cratepat = regexp.MustCompile(`\[([^\]]+)\]`)
+	movepat  = regexp.MustCompile(`^move ([0-9]+) from ([0-9]+) to ([0-9]+)$`)
+)
+
+// Parse reads the drawing of the stacks and the list of moves. The drawing is
+// read by column position: each crate belongs to the stack whose label (in the
+// row of numbers under the drawing) lies underneath it, so there can be any
+// number of stacks, labels can have more than one digit, and crates can have
+// names longer than one letter. A drawing that doesn't line up is an error.
+func Parse(lines []string) (*Cargo, error) {
+	labelIx := -1
+	for i, l := range lines {
+		if t := strings.TrimSpace(l); t != "" && !strings.Contains(t, "[") {
+			labelIx = i
 			break
 		}
-		items.Push(l)
+	}
+	if labelIx < 0 {
+		return nil, fmt.Errorf("no row of stack labels found")
 	}
 
+	labelRow := lines[labelIx]
+	spans := labelpat.FindAllStringIndex(labelRow, -1)
+	if strings.TrimSpace(labelpat.ReplaceAllString(labelRow, "")) != "" {
+		return nil, fmt.Errorf("line %d: label row should only have numbers: %q", labelIx+1, labelRow)
+	}
 	cargo := &Cargo{
-		stacks:   make([]Stack, nstacks),
+		stacks:   make([]Stack, len(spans)),
 		commands: make([]Command, 0),
 	}
+	stackIx := make(map[int]int)
+	for i, sp := range spans {
+		n, _ := strconv.Atoi(labelRow[sp[0]:sp[1]])
+		if _, dup := stackIx[n]; dup {
+			return nil, fmt.Errorf("line %d: stack label %d appears twice", labelIx+1, n)
+		}
+		stackIx[n] = i
+		cargo.labels = append(cargo.labels, n)
+	}
 
-	for l, ok := items.Pop(); ok; l, ok = items.Pop() {
-		for i := 0; i < nstacks; i++ {
-			if len(l) > i*4+1 {
-				crate := l[i*4+1 : i*4+2]
-				if crate != " " {
-					cargo.stacks[i].Push(crate)
+	// work up from the bottom of the drawing so crates are pushed in order
+	for row := labelIx - 1; row >= 0; row-- {
+		l := lines[row]
+		if strings.TrimSpace(cratepat.ReplaceAllString(l, "")) != "" {
+			return nil, fmt.Errorf("line %d: unexpected text outside crates: %q", row+1, l)
+		}
+		height := labelIx - 1 - row // crates each stack should already hold
+		used := make(map[int]bool)
+		for _, m := range cratepat.FindAllStringSubmatchIndex(l, -1) {
+			ix := -1
+			for i, sp := range spans {
+				if sp[0] < m[1] && sp[1] > m[0] {
+					if ix >= 0 {
+						return nil, fmt.Errorf("line %d: crate at column %d is over more than one label", row+1, m[0]+1)
+					}
+					ix = i
 				}
 			}
+			if ix < 0 {
+				return nil, fmt.Errorf("line %d: crate at column %d isn't over any label", row+1, m[0]+1)
+			}
+			if used[ix] {
+				return nil, fmt.Errorf("line %d: two crates over stack %d", row+1, cargo.labels[ix])
+			}
+			used[ix] = true
+			if cargo.stacks[ix].Len() != height {
+				return nil, fmt.Errorf("line %d: crate at column %d is floating above stack %d", row+1, m[0]+1, cargo.labels[ix])
+			}
+			cargo.stacks[ix].Push(l[m[2]:m[3]])
 		}
 	}
 
-	pat := regexp.MustCompile("move ([0-9]+) from ([0-9]) to ([0-9])")
-	for ; ix < len(lines); ix++ {
-		m := pat.FindStringSubmatch(lines[ix])
-		if len(m) == 4 {
-			qty, _ := strconv.Atoi(m[1])
-			fr, _ := strconv.Atoi(m[2])
-			to, _ := strconv.Atoi(m[3])
-			cargo.commands = append(cargo.commands, Command{Qty: qty, FrIx: fr - 1, ToIx: to - 1})
+	for i := labelIx + 1; i < len(lines); i++ {
+		l := strings.TrimSpace(lines[i])
+		if l == "" {
+			continue
 		}
+		m := movepat.FindStringSubmatch(l)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: can't parse move %q", i+1, l)
+		}
+		qty, _ := strconv.Atoi(m[1])
+		fr, _ := strconv.Atoi(m[2])
+		to, _ := strconv.Atoi(m[3])
+		frIx, ok1 := stackIx[fr]
+		toIx, ok2 := stackIx[to]
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("line %d: no such stack in %q", i+1, l)
+		}
+		cargo.commands = append(cargo.commands, Command{Qty: qty, FrIx: frIx, ToIx: toIx})
 	}
-	return cargo
+	return cargo, nil
 }
 
 func (c *Cargo) MoveSingle(cmd Command) {
@@ -74,22 +128,91 @@ func (c *Cargo) MoveSingle(cmd Command) {
 	}
 }
 
-func (c *Cargo) ExecSingle() {
-	for _, cmd := range c.commands {
-		c.MoveSingle(cmd)
-	}
-}
-
 func (c *Cargo) MoveMulti(cmd Command) {
 	if crates, ok := c.stacks[cmd.FrIx].PopN(cmd.Qty); ok {
 		c.stacks[cmd.ToIx].PushN(crates)
 	}
 }
 
-func (c *Cargo) ExecMulti() {
+// Exec runs all the commands with the given crane.
+func (c *Cargo) Exec(crane Crane) {
 	for _, cmd := range c.commands {
-		c.MoveMulti(cmd)
+		crane.Move(c, cmd)
+	}
+}
+
+// Check reports an error if command i (counting from 1) asks for more crates
+// than its stack holds.
+func (c *Cargo) Check(i int, cmd Command) error {
+	have := c.stacks[cmd.FrIx].Len()
+	if cmd.Qty > have {
+		return fmt.Errorf("command %d (move %d from %d to %d): stack %d has %d crates and stack %d has %d",
+			i, cmd.Qty, c.label(cmd.FrIx), c.label(cmd.ToIx),
+			c.label(cmd.FrIx), have, c.label(cmd.ToIx), c.stacks[cmd.ToIx].Len())
 	}
+	return nil
+}
+
+// ExecStrict runs the commands with the given crane, checking each one first.
+// It stops at the first command that can't be done in full; the cargo is left
+// as it was after the command before.
+func (c *Cargo) ExecStrict(crane Crane) error {
+	for i, cmd := range c.commands {
+		if err := c.Check(i+1, cmd); err != nil {
+			return err
+		}
+		crane.Move(c, cmd)
+	}
+	return nil
+}
+
+// Shortfall records a command that didn't move as many crates as it asked for.
+type Shortfall struct {
+	Index int // counting from 1
+	Cmd   Command
+	Had   int // crates on the source stack beforehand
+	Moved int
+}
+
+func (s Shortfall) String() string {
+	what := "skipped"
+	if s.Moved > 0 {
+		what = fmt.Sprintf("moved only %d", s.Moved)
+	}
+	return fmt.Sprintf("command %d: asked for %d crates, stack had %d, %s", s.Index, s.Cmd.Qty, s.Had, what)
+}
+
+// ExecLenient runs all the commands with the given crane, letting it do what
+// it can with commands that ask for too many crates, and returns a log of
+// those commands.
+func (c *Cargo) ExecLenient(crane Crane) []Shortfall {
+	var short []Shortfall
+	for i, cmd := range c.commands {
+		had := c.stacks[cmd.FrIx].Len()
+		crane.Move(c, cmd)
+		if cmd.Qty <= had {
+			continue
+		}
+		moved := had - c.stacks[cmd.FrIx].Len()
+		if cmd.FrIx == cmd.ToIx {
+			moved = 0
+		}
+		short = append(short, Shortfall{Index: i + 1, Cmd: cmd, Had: had, Moved: moved})
+	}
+	return short
+}
+
+// Clone returns a copy of the cargo that can be changed independently.
+func (c *Cargo) Clone() *Cargo {
+	clone := &Cargo{
+		stacks:   make([]Stack, len(c.stacks)),
+		labels:   c.labels,
+		commands: c.commands,
+	}
+	for i, s := range c.stacks {
+		clone.stacks[i].PushN(s.s)
+	}
+	return clone
 }
 
 func (c *Cargo) Tops() string {
@@ -120,6 +243,12 @@ func (s *Stack) PushN(a []string) {
 	s.s = append(s.s, a...)
 }
 
+// InsertBottom puts the crates in a underneath everything already on the
+// stack, keeping their order.
+func (s *Stack) InsertBottom(a []string) {
+	s.s = append(append([]string(nil), a...), s.s...)
+}
+
 func (s *Stack) Pop() (string, bool) {
 	if a, ok := s.PopN(1); ok {
 		return a[0], ok
@@ -128,7 +257,7 @@ func (s *Stack) Pop() (string, bool) {
 }
 
 func (s *Stack) PopN(n int) ([]string, bool) {
-	if len(s.s) >= n {
+	if n >= 0 && len(s.s) >= n {
 		r := s.s[len(s.s)-n:]
 		s.s = s.s[:len(s.s)-n]
 		return r, true
@@ -136,6 +265,10 @@ func (s *Stack) PopN(n int) ([]string, bool) {
 	return nil, false
 }
 
+func (s *Stack) Len() int {
+	return len(s.s)
+}
+
 func (s *Stack) Top() (string, bool) {
 	if len(s.s) > 0 {
 		r := s.s[len(s.s)-1]
@@ -148,19 +281,95 @@ func (s *Stack) String() string {
 	return strings.Join(s.s, "|")
 }
 
-func part1(lines []string) {
-	cargo := Parse(lines)
-	cargo.ExecSingle()
+// runs the commands, treating moves that ask for more crates than there are
+// according to mode
+func run(cargo *Cargo, crane Crane, mode string) {
+	switch mode {
+	case "strict":
+		if err := cargo.ExecStrict(crane); err != nil {
+			log.Fatal(err)
+		}
+	case "lenient":
+		for _, s := range cargo.ExecLenient(crane) {
+			fmt.Fprintln(os.Stderr, s)
+		}
+	default:
+		cargo.Exec(crane)
+	}
+}
+
+func part1(lines []string, mode string) {
+	cargo, err := Parse(lines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	run(cargo, CrateMover9000{}, mode)
 	fmt.Println(cargo.Tops())
 }
 
-func part2(lines []string) {
-	cargo := Parse(lines)
-	cargo.ExecMulti()
+func part2(lines []string, mode string) {
+	cargo, err := Parse(lines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	run(cargo, CrateMover9001{}, mode)
 	fmt.Println(cargo.Tops())
 }
 
+// runs every registered crane model on the same input
+func allCranes(lines []string) {
+	cargo, err := Parse(lines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, crane := range RegisteredCranes() {
+		c := cargo.Clone()
+		c.Exec(crane)
+		fmt.Printf("%-30s %s\n", crane.Name(), c.Tops())
+	}
+}
+
+// steps through the commands interactively, drawing the stacks each time
+func replay(lines []string, crane Crane) {
+	cargo, err := Parse(lines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	r := NewReplay(cargo, crane)
+	in := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Println(r)
+		fmt.Print("[enter/n]ext, [b]ack, command number, [q]uit> ")
+		if !in.Scan() {
+			return
+		}
+		cmd := strings.TrimSpace(in.Text())
+		switch cmd {
+		case "", "n":
+			r.Forward()
+		case "b":
+			r.Back()
+		case "q":
+			return
+		default:
+			n, err := strconv.Atoi(cmd)
+			if err == nil {
+				err = r.Jump(n)
+			}
+			if err != nil {
+				fmt.Println(err)
+			}
+		}
+	}
+}
+
 func main() {
+	all := flag.Bool("cranes", false, "run every registered crane model")
+	step := flag.Int("replay", 0, "step through the moves for part 1 or 2")
+	puzzle := flag.Int("puzzle", -1, "write the puzzle that's left after this many moves for part 1")
+	mode := flag.String("mode", "", "strict: stop at an impossible move; lenient: log impossible moves to stderr")
+	flag.Parse()
+
 	f, err := os.Open("./input.txt")
 	if err != nil {
 		log.Fatal(err)
@@ -170,6 +379,32 @@ func main() {
 		log.Fatal(err)
 	}
 	lines := strings.Split(string(b), "\n")
-	part1(lines)
-	part2(lines)
+	if *all {
+		allCranes(lines)
+		return
+	}
+	if *puzzle >= 0 {
+		cargo, err := Parse(lines)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r := NewReplay(cargo, CrateMover9000{})
+		if err := r.Jump(*puzzle); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := r.Puzzle().WriteTo(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	switch *step {
+	case 1:
+		replay(lines, CrateMover9000{})
+		return
+	case 2:
+		replay(lines, CrateMover9001{})
+		return
+	}
+	part1(lines, *mode)
+	part2(lines, *mode)
 }
@@ -0,0 +1,98 @@
+package main
+
+import "fmt"
+
+// Replay runs the commands one at a time with a crane and keeps a snapshot of
+// the cargo after every command, so it can step backwards as well as forwards
+// or jump straight to any command.
+type Replay struct {
+	crane Crane
+	snaps []*Cargo // snaps[n] is the cargo after the first n commands
+	pos   int
+}
+
+// NewReplay starts a replay at the beginning of the cargo's commands. The cargo
+// itself isn't changed.
+func NewReplay(c *Cargo, crane Crane) *Replay {
+	return &Replay{
+		crane: crane,
+		snaps: []*Cargo{c.Clone()},
+	}
+}
+
+// Len is the number of commands in the replay.
+func (r *Replay) Len() int {
+	return len(r.snaps[0].commands)
+}
+
+// Pos is the number of commands that have been run.
+func (r *Replay) Pos() int {
+	return r.pos
+}
+
+// Cargo is the state after Pos commands; it must not be changed.
+func (r *Replay) Cargo() *Cargo {
+	return r.snaps[r.pos]
+}
+
+// Last returns the command that was run to get to the current state.
+func (r *Replay) Last() (Command, bool) {
+	if r.pos == 0 {
+		return Command{}, false
+	}
+	return r.snaps[0].commands[r.pos-1], true
+}
+
+// Forward runs the next command. It returns false at the end.
+func (r *Replay) Forward() bool {
+	if r.pos >= r.Len() {
+		return false
+	}
+	// snapshots are only ever made in order, so stateful cranes see the
+	// commands in the order they'd normally run
+	if r.pos+1 == len(r.snaps) {
+		next := r.snaps[r.pos].Clone()
+		r.crane.Move(next, next.commands[r.pos])
+		r.snaps = append(r.snaps, next)
+	}
+	r.pos++
+	return true
+}
+
+// Back undoes the last command. It returns false at the beginning.
+func (r *Replay) Back() bool {
+	if r.pos == 0 {
+		return false
+	}
+	r.pos--
+	return true
+}
+
+// Jump moves to the state after n commands.
+func (r *Replay) Jump(n int) error {
+	if n < 0 || n > r.Len() {
+		return fmt.Errorf("can't jump to command %d of %d", n, r.Len())
+	}
+	for r.pos < n {
+		r.Forward()
+	}
+	r.pos = n
+	return nil
+}
+
+// Puzzle returns the current state together with the commands that haven't
+// been run yet, which is a new puzzle with the same answer.
+func (r *Replay) Puzzle() *Cargo {
+	p := r.Cargo().Clone()
+	p.commands = p.commands[r.pos:]
+	return p
+}
+
+// String shows where the replay is and draws the current state.
+func (r *Replay) String() string {
+	s := fmt.Sprintf("%s: %d of %d commands", r.crane.Name(), r.pos, r.Len())
+	if cmd, ok := r.Last(); ok {
+		s += fmt.Sprintf(", last was move %d from %d to %d", cmd.Qty, r.Cargo().label(cmd.FrIx), r.Cargo().label(cmd.ToIx))
+	}
+	return s + "\n\n" + r.Cargo().Drawing()
+}
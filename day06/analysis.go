@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	packetSize  = 4
+	messageSize = 14
+)
+
+// Analysis describes the windows of distinct bytes in a stream. It's worked
+// out differently from Detector (by remembering where each byte was last
+// seen), so the two can be checked against each other.
+type Analysis struct {
+	Len      int   // bytes in the stream
+	Packets  []int // every start-of-packet position, as the puzzle counts them
+	Messages []int // every start-of-message position
+	// the longest run of distinct bytes; LongestAt is the index of its first
+	// byte (the first such run if there are several)
+	Longest   int
+	LongestAt int
+	// Lengths counts, for each length L, the positions where the longest run
+	// of distinct bytes ending there is exactly L bytes long.
+	Lengths map[int]int
+
+	last  [256]int // 1 + the index where each byte was last seen
+	start int      // index of the first byte of the current run
+}
+
+func NewAnalysis() *Analysis {
+	return &Analysis{Lengths: make(map[int]int)}
+}
+
+// AddByte adds the next byte of the stream.
+func (a *Analysis) AddByte(b byte) {
+	if a.last[b] > a.start {
+		a.start = a.last[b]
+	}
+	a.last[b] = a.Len + 1
+	run := a.Len - a.start + 1
+	a.Lengths[run]++
+	if run > a.Longest {
+		a.Longest = run
+		a.LongestAt = a.start
+	}
+	a.Len++
+	if run >= packetSize {
+		a.Packets = append(a.Packets, a.Len)
+	}
+	if run >= messageSize {
+		a.Messages = append(a.Messages, a.Len)
+	}
+}
+
+// Distinct returns how many windows of n bytes are all different.
+func (a *Analysis) Distinct(n int) int {
+	total := 0
+	for l, count := range a.Lengths {
+		if l >= n {
+			total += count
+		}
+	}
+	return total
+}
+
+// some positions, and how many more there are
+func positions(p []int) string {
+	const most = 5
+	s := fmt.Sprint(p)
+	if len(p) > most {
+		s = fmt.Sprint(p[:most])
+		s = s[:len(s)-1] + fmt.Sprintf(" ... and %d more]", len(p)-most)
+	}
+	return s
+}
+
+func (a *Analysis) String() string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "%d bytes\n", a.Len)
+	fmt.Fprintf(&sb, "  start-of-packet:  %d %s\n", len(a.Packets), positions(a.Packets))
+	fmt.Fprintf(&sb, "  start-of-message: %d %s\n", len(a.Messages), positions(a.Messages))
+	fmt.Fprintf(&sb, "  longest distinct run: %d bytes at %d\n", a.Longest, a.LongestAt)
+	lengths := make([]int, 0, len(a.Lengths))
+	for l := range a.Lengths {
+		lengths = append(lengths, l)
+	}
+	sort.Ints(lengths)
+	sb.WriteString("  distinct run lengths:")
+	for _, l := range lengths {
+		fmt.Fprintf(&sb, " %d:%d", l, a.Lengths[l])
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// Analyze returns an Analysis of each line of r.
+func Analyze(r io.Reader) ([]*Analysis, error) {
+	var all []*Analysis
+	a := NewAnalysis()
+	err := eachLine(r, func(b byte) { a.AddByte(b) }, func(line int) {
+		all = append(all, a)
+		a = NewAnalysis()
+	})
+	return all, err
+}
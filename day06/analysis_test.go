@@ -0,0 +1,119 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// runs a Detector reporting every marker over s
+func detect(s string) map[int][]int {
+	got := map[int][]int{}
+	d := NewDetector(packetSize, messageSize)
+	d.All = true
+	d.OnMarker = func(m Marker) { got[m.Size] = append(got[m.Size], m.Pos) }
+	d.Write([]byte(s))
+	return got
+}
+
+func analyze(s string) *Analysis {
+	a := NewAnalysis()
+	for i := 0; i < len(s); i++ {
+		a.AddByte(s[i])
+	}
+	return a
+}
+
+func compare(t *testing.T, name, s string) {
+	t.Helper()
+	markers := detect(s)
+	a := analyze(s)
+	if !reflect.DeepEqual(markers[packetSize], a.Packets) {
+		t.Errorf("%s: detector packets %v, analysis %v", name, markers[packetSize], a.Packets)
+	}
+	if !reflect.DeepEqual(markers[messageSize], a.Messages) {
+		t.Errorf("%s: detector messages %v, analysis %v", name, markers[messageSize], a.Messages)
+	}
+	if a.Distinct(packetSize) != len(a.Packets) || a.Distinct(messageSize) != len(a.Messages) {
+		t.Errorf("%s: Distinct disagrees with the marker lists", name)
+	}
+}
+
+func adversarial() map[string]string {
+	streams := map[string]string{
+		"empty":         "",
+		"one byte":      "a",
+		"all the same":  strings.Repeat("z", 100),
+		"short":         "abc",
+		"exactly 4":     "abcd",
+		"exactly 14":    "abcdefghijklmn",
+		"13 then a dup": "abcdefghijklma",
+		"dup just out":  "abcdefghijklmnanopq",
+		"period 4":      strings.Repeat("abcd", 20),
+		"period 13":     strings.Repeat("abcdefghijklm", 5),
+		"period 14":     strings.Repeat("abcdefghijklmn", 5),
+		"palindromes":   strings.Repeat("abcdefghijklmnmlkjihgfedcb", 4),
+		"alphabet":      "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyz",
+		"all bytes":     allBytes(),
+		"dup at start":  "aabcdefghijklmnop",
+		"dup at end":    "abcdefghijklmnopp",
+	}
+	return streams
+}
+
+func allBytes() string {
+	b := make([]byte, 512)
+	for i := range b {
+		b[i] = byte(i * 7)
+	}
+	return string(b)
+}
+
+func TestDetectorMatchesAnalysis(t *testing.T) {
+	for name, s := range adversarial() {
+		compare(t, name, s)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		// small alphabets make markers rare, big ones make them common
+		alphabet := 2 + r.Intn(30)
+		b := make([]byte, r.Intn(200))
+		for j := range b {
+			b[j] = byte('a' + r.Intn(alphabet))
+		}
+		compare(t, string(b), string(b))
+	}
+}
+
+func TestAnalysisSample(t *testing.T) {
+	a := analyze("mjqjpqmgbljsphdztnvjfqwrcgsmlb")
+	if a.Packets[0] != 7 || a.Messages[0] != 19 {
+		t.Errorf("first markers %d %d, want 7 19", a.Packets[0], a.Messages[0])
+	}
+	if a.Longest != 18 || a.LongestAt != 12 {
+		t.Errorf("longest run %d at %d, want 18 at 12", a.Longest, a.LongestAt)
+	}
+	total := 0
+	for _, n := range a.Lengths {
+		total += n
+	}
+	if total != a.Len {
+		t.Errorf("run lengths cover %d positions, want %d", total, a.Len)
+	}
+}
+
+func TestFirstMarkers(t *testing.T) {
+	input := "mjqjpqmgbljsphdztnvjfqwrcgsmlb\nbvwbjplbgvbhsrlpgdmjqwftvncz\nabc\n"
+	var got [][]int
+	err := FirstMarkers(strings.NewReader(input), []int{4, 14}, func(line int, pos []int) {
+		got = append(got, append([]int(nil), pos...))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]int{{7, 19}, {5, 23}, {-1, -1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
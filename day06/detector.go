@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"io"
+)
+
+// Marker is a place in a stream where the last Size bytes are all different.
+// Pos is the number of bytes read when the marker is complete, which is the
+// answer the puzzle wants.
+type Marker struct {
+	Size int
+	Pos  int
+}
+
+// window keeps counts of the bytes in the last n bytes of the stream, and how
+// many byte values occur more than once, so each new byte costs O(1).
+type window struct {
+	n      int
+	counts [256]int
+	dups   int
+	found  bool
+}
+
+// Detector looks for markers of several sizes at once, in a single pass over a
+// stream. It only remembers the last few bytes, so the stream can be any
+// length.
+type Detector struct {
+	// All reports every marker rather than just the first of each size.
+	All bool
+	// OnMarker is called for each marker as it's found.
+	OnMarker func(Marker)
+
+	windows []window
+	ring    []byte // the last len(ring) bytes; ring[pos%len(ring)] is the oldest
+	pos     int
+}
+
+func NewDetector(sizes ...int) *Detector {
+	d := &Detector{windows: make([]window, len(sizes))}
+	longest := 1
+	for i, n := range sizes {
+		if n < 1 {
+			panic("marker size must be positive")
+		}
+		d.windows[i].n = n
+		if n > longest {
+			longest = n
+		}
+	}
+	d.ring = make([]byte, longest)
+	return d
+}
+
+// Reset starts a new stream.
+func (d *Detector) Reset() {
+	for i := range d.windows {
+		d.windows[i] = window{n: d.windows[i].n}
+	}
+	d.pos = 0
+}
+
+// Pos is the number of bytes seen since the stream started.
+func (d *Detector) Pos() int {
+	return d.pos
+}
+
+// Done is true once the first marker of every size has been seen, so a caller
+// that only wants those can stop reading.
+func (d *Detector) Done() bool {
+	for _, w := range d.windows {
+		if !w.found {
+			return false
+		}
+	}
+	return true
+}
+
+// AddByte adds the next byte of the stream.
+func (d *Detector) AddByte(b byte) {
+	slot := d.pos % len(d.ring)
+	for i := range d.windows {
+		w := &d.windows[i]
+		w.counts[b]++
+		if w.counts[b] == 2 {
+			w.dups++
+		}
+		if d.pos >= w.n {
+			// the byte leaving the window arrived w.n bytes ago
+			old := d.ring[(d.pos-w.n)%len(d.ring)]
+			w.counts[old]--
+			if w.counts[old] == 1 {
+				w.dups--
+			}
+		}
+		if d.pos+1 >= w.n && w.dups == 0 && (d.All || !w.found) {
+			w.found = true
+			if d.OnMarker != nil {
+				d.OnMarker(Marker{Size: w.n, Pos: d.pos + 1})
+			}
+		}
+	}
+	d.ring[slot] = b
+	d.pos++
+}
+
+// Write adds p to the stream, so a Detector can be the target of io.Copy.
+func (d *Detector) Write(p []byte) (int, error) {
+	for _, b := range p {
+		d.AddByte(b)
+	}
+	return len(p), nil
+}
+
+// eachLine reads r one byte at a time, treating each line as a separate
+// stream. add is called for each byte and end at the end of each line. Lines
+// are never held in memory, so they can be as long as they like.
+func eachLine(r io.Reader, add func(byte), end func(line int)) error {
+	br := bufio.NewReader(r)
+	line := 0
+	started := false
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			if started {
+				end(line)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if b == '\n' {
+			end(line)
+			line++
+			started = false
+			continue
+		}
+		started = true
+		add(b)
+	}
+}
+
+// FirstMarkers calls f with the position of the first marker of each size on
+// each line of r (-1 if there isn't one).
+func FirstMarkers(r io.Reader, sizes []int, f func(line int, pos []int)) error {
+	d := NewDetector(sizes...)
+	pos := make([]int, len(sizes))
+	d.OnMarker = func(m Marker) {
+		for i, n := range sizes {
+			if n == m.Size && pos[i] < 0 {
+				pos[i] = m.Pos
+			}
+		}
+	}
+	reset := func() {
+		d.Reset()
+		for i := range pos {
+			pos[i] = -1
+		}
+	}
+	reset()
+	return eachLine(r, d.AddByte, func(line int) {
+		f(line, pos)
+		reset()
+	})
+}
@@ -1,44 +1,34 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
-	"strings"
 )
 
-func findFirstDiff(s string, n int) int {
-	for i := 0; i < len(s)-n; i++ {
-		found := true
-	dupcheck:
-		for j := i; j < n+i-1; j++ {
-			for k := j + 1; k < n+i; k++ {
-				if s[j] == s[k] {
-					found = false
-					i = j // we can skip ahead this far
-					break dupcheck
-				}
-			}
-		}
-		if found {
-			return i + n
-		}
-	}
-	return -1
-}
-
 func main() {
+	analyze := flag.Bool("analyze", false, "describe every distinct window in each line")
+	flag.Parse()
+
 	f, err := os.Open("./input.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
-	b, err := ioutil.ReadAll(f)
+	if *analyze {
+		all, err := Analyze(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, a := range all {
+			fmt.Printf("line %d: %s", i+1, a)
+		}
+		return
+	}
+	err = FirstMarkers(f, []int{4, 14}, func(line int, pos []int) {
+		fmt.Println(pos[0], pos[1])
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
-	lines := strings.Split(string(b), "\n")
-	for _, l := range lines {
-		fmt.Println(findFirstDiff(l, 4), findFirstDiff(l, 14))
-	}
 }
@@ -9,11 +9,15 @@ import (
 	"strings"
 )
 
+// FileTree is a directory. Its size is kept up to date as things are added
+// and removed, so Dirs and Files should only be changed with AddDir, AddFile,
+// RemoveDir and RemoveFile.
 type FileTree struct {
 	Name   string
 	Dirs   map[string]*FileTree
 	Files  map[string]int
 	Parent *FileTree
+	size   int // total of all the files in this directory and below
 }
 
 func NewFileTree(name string, parent *FileTree) *FileTree {
@@ -36,14 +40,47 @@ func (f *FileTree) Print(indent int) {
 }
 
 func (f *FileTree) Size() int {
-	t := 0
-	for _, d := range f.Dirs {
-		t += d.Size()
+	return f.size
+}
+
+// grow adds delta to the size of this directory and all the ones above it.
+func (f *FileTree) grow(delta int) {
+	for d := f; d != nil; d = d.Parent {
+		d.size += delta
 	}
-	for _, size := range f.Files {
-		t += size
+}
+
+// AddFile adds a file, or changes its size if it's already there.
+func (f *FileTree) AddFile(name string, size int) {
+	old := f.Files[name]
+	f.Files[name] = size
+	f.grow(size - old)
+}
+
+func (f *FileTree) RemoveFile(name string) {
+	if size, ok := f.Files[name]; ok {
+		delete(f.Files, name)
+		f.grow(-size)
+	}
+}
+
+// AddDir returns the named subdirectory, creating it if it isn't there.
+func (f *FileTree) AddDir(name string) *FileTree {
+	if d, ok := f.Dirs[name]; ok {
+		return d
+	}
+	d := NewFileTree(name, f)
+	f.Dirs[name] = d
+	return d
+}
+
+// RemoveDir removes the named subdirectory and everything in it.
+func (f *FileTree) RemoveDir(name string) {
+	if d, ok := f.Dirs[name]; ok {
+		delete(f.Dirs, name)
+		f.grow(-d.size)
+		d.Parent = nil
 	}
-	return t
 }
 
 type Visitor interface {
@@ -126,10 +163,10 @@ outer:
 						continue outer
 					}
 					if item[0] == "dir" {
-						current.Dirs[item[1]] = NewFileTree(item[1], current)
+						current.AddDir(item[1])
 					} else {
 						sz, _ := strconv.Atoi(item[0])
-						current.Files[item[1]] = sz
+						current.AddFile(item[1], sz)
 					}
 				}
 			}
@@ -143,8 +180,8 @@ type totaller struct {
 }
 
 func (t *totaller) Visit(f *FileTree) {
-	if f.Size() <= 100000 {
-		t.total += f.Size()
+	if sz := f.Size(); sz <= 100000 {
+		t.total += sz
 	}
 }
 
@@ -0,0 +1,89 @@
+package aoc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExpectedFile is the name of the file in a day's directory that holds the
+// known answers for its inputs.
+const ExpectedFile = "expected.txt"
+
+// Expected maps an input file name and part ("inputsample.txt", 1) to the
+// answer it should produce.
+type Expected map[string]map[int]string
+
+// ReadExpected loads the expected answers for the day in dir. The file has a
+// line per answer of the form
+//
+//	inputsample.txt part1: 95437
+//
+// and lines starting with # are comments. A missing file is not an error.
+func ReadExpected(dir string) (Expected, error) {
+	exp := make(Expected)
+	f, err := os.Open(filepath.Join(dir, ExpectedFile))
+	if os.IsNotExist(err) {
+		return exp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		var input string
+		var part int
+		head, answer, ok := strings.Cut(l, ":")
+		if ok {
+			_, err = fmt.Sscanf(head, "%s part%d", &input, &part)
+		}
+		if !ok || err != nil {
+			return nil, fmt.Errorf("%s line %d: can't parse %q", ExpectedFile, n, l)
+		}
+		exp.Set(input, part, strings.TrimSpace(answer))
+	}
+	return exp, scanner.Err()
+}
+
+func (e Expected) Set(input string, part int, answer string) {
+	if e[input] == nil {
+		e[input] = make(map[int]string)
+	}
+	e[input][part] = answer
+}
+
+func (e Expected) Get(input string, part int) (string, bool) {
+	a, ok := e[input][part]
+	return a, ok
+}
+
+// Write saves the expected answers into dir.
+func (e Expected) Write(dir string) error {
+	var b strings.Builder
+	b.WriteString("# expected answers: <input file> part<n>: <answer>\n")
+	for _, input := range sortedKeys(e) {
+		for part := 1; part <= 2; part++ {
+			if a, ok := e[input][part]; ok {
+				fmt.Fprintf(&b, "%s part%d: %s\n", input, part, a)
+			}
+		}
+	}
+	return os.WriteFile(filepath.Join(dir, ExpectedFile), []byte(b.String()), 0644)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,195 @@
+package aoc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ModuleBase is the import path of the repository, which the LegacyYear days
+// and lib live under.
+const ModuleBase = "github.com/kentquirk/aoc2022"
+
+// LibModule is the import path of the shared libraries.
+const LibModule = ModuleBase + "/lib"
+
+// DayModuleBase is the module path that the days of later years live under,
+// as DayModuleBase/2023/day01. Nothing imports a day's main package, so its
+// module path doesn't need to be where the repository lives, and this keeps
+// the year the repository was started out of every new day.
+const DayModuleBase = "aoc"
+
+// LegacyYear is the year whose days live directly in the repository root
+// (day01, day02, ...) rather than in a year directory (2023/day01).
+const LegacyYear = 2022
+
+// Puzzle identifies one day's puzzle.
+type Puzzle struct {
+	Year int
+	Day  int
+}
+
+func (p Puzzle) String() string {
+	return fmt.Sprintf("%d day %d", p.Year, p.Day)
+}
+
+// DirName is the name of the day's directory, without the year.
+func (p Puzzle) DirName() string {
+	return fmt.Sprintf("day%02d", p.Day)
+}
+
+func (p Puzzle) Valid() error {
+	if p.Year < 2015 {
+		return fmt.Errorf("no Advent of Code in %d", p.Year)
+	}
+	if p.Day < 1 || p.Day > 25 {
+		return fmt.Errorf("day must be from 1 to 25, not %d", p.Day)
+	}
+	return nil
+}
+
+// Layout knows where things are in the repository. Each year has a directory
+// named for the year holding a module per day; the shared libraries live in
+// lib, which serves every year. The days of LegacyYear predate this layout and
+// stay at the top level.
+type Layout struct {
+	Root string
+}
+
+// FindLayout looks for the repository root, which is the nearest directory at
+// or above start that contains lib/go.mod.
+func FindLayout(start string) (Layout, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return Layout{}, err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "lib", "go.mod")); err == nil {
+			return Layout{Root: dir}, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Layout{}, fmt.Errorf("no repository root (a directory with lib/go.mod) above %s", start)
+		}
+		dir = parent
+	}
+}
+
+// YearDir is where a year's days go.
+func (l Layout) YearDir(year int) string {
+	return filepath.Join(l.Root, strconv.Itoa(year))
+}
+
+// Dir returns the directory for a puzzle. For LegacyYear that is the top-level
+// directory if it exists, otherwise the year directory.
+func (l Layout) Dir(p Puzzle) string {
+	if p.Year == LegacyYear {
+		legacy := filepath.Join(l.Root, p.DirName())
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return filepath.Join(l.YearDir(p.Year), p.DirName())
+}
+
+// ModulePath returns the import path of the module in dir: under ModuleBase
+// for the top-level LegacyYear days, and under DayModuleBase for year
+// directories.
+func (l Layout) ModulePath(dir string) (string, error) {
+	rel, err := filepath.Rel(l.Root, dir)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if strings.HasPrefix(rel, "../") || rel == ".." {
+		return "", fmt.Errorf("%s is outside the repository", dir)
+	}
+	if !strings.Contains(rel, "/") {
+		return ModuleBase + "/" + rel, nil
+	}
+	return DayModuleBase + "/" + rel, nil
+}
+
+// Years returns the years that have any days, in order.
+func (l Layout) Years() []int {
+	seen := map[int]bool{}
+	if len(l.legacyDays()) > 0 {
+		seen[LegacyYear] = true
+	}
+	entries, _ := os.ReadDir(l.Root)
+	for _, e := range entries {
+		if y, err := strconv.Atoi(e.Name()); err == nil && e.IsDir() {
+			seen[y] = true
+		}
+	}
+	var years []int
+	for y := range seen {
+		years = append(years, y)
+	}
+	sort.Ints(years)
+	return years
+}
+
+// Days returns the puzzles that have directories for a year, in order.
+func (l Layout) Days(year int) []Puzzle {
+	seen := map[int]bool{}
+	if year == LegacyYear {
+		for _, d := range l.legacyDays() {
+			seen[d] = true
+		}
+	}
+	for _, d := range dayDirs(l.YearDir(year)) {
+		seen[d] = true
+	}
+	var days []Puzzle
+	for d := range seen {
+		days = append(days, Puzzle{Year: year, Day: d})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day < days[j].Day })
+	return days
+}
+
+func (l Layout) legacyDays() []int {
+	return dayDirs(l.Root)
+}
+
+func dayDirs(dir string) []int {
+	var days []int
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if !e.IsDir() || !strings.HasPrefix(e.Name(), "day") {
+			continue
+		}
+		if d, err := strconv.Atoi(strings.TrimPrefix(e.Name(), "day")); err == nil {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// ResolveDir picks a day's directory for a tool: by year and day if day is
+// set (year defaults to LegacyYear), otherwise dir as given.
+func ResolveDir(dir string, year, day int) (string, error) {
+	if day == 0 {
+		return dir, nil
+	}
+	if year == 0 {
+		year = LegacyYear
+	}
+	p := Puzzle{Year: year, Day: day}
+	if err := p.Valid(); err != nil {
+		return "", err
+	}
+	l, err := FindLayout(".")
+	if err != nil {
+		return "", err
+	}
+	d := l.Dir(p)
+	if _, err := os.Stat(d); err != nil {
+		return "", fmt.Errorf("no directory for %v (looked for %s)", p, d)
+	}
+	return d, nil
+}
@@ -0,0 +1,147 @@
+package aoc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// makeRoot builds a small repository: lib, a template, two top-level 2022 days
+// and one 2021 day.
+func makeRoot(t *testing.T) Layout {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"lib/go.mod":             "module " + LibModule + "\n",
+		"_template/main.go":      "package main\n\nfunc main() {}\n",
+		"_template/input.txt":    "",
+		"_template/go.mod":       "module template\n",
+		"day01/main.go":          "package main\n",
+		"day17/main.go":          "package main\n",
+		"2021/day03/main.go":     "package main\n",
+		"2021/notes/README.md":   "",
+		"day17/inputsample.txt":  "",
+		"somewhere/deep/file.go": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Layout{Root: root}
+}
+
+func TestFindLayout(t *testing.T) {
+	l := makeRoot(t)
+	found, err := FindLayout(filepath.Join(l.Root, "somewhere", "deep"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Root != l.Root {
+		t.Errorf("found root %s, want %s", found.Root, l.Root)
+	}
+	if _, err := FindLayout(t.TempDir()); err == nil {
+		t.Error("found a root where there isn't one")
+	}
+}
+
+func TestDir(t *testing.T) {
+	l := makeRoot(t)
+	tests := []struct {
+		p    Puzzle
+		want string
+	}{
+		{Puzzle{2022, 17}, "day17"},
+		{Puzzle{2022, 18}, "2022/day18"}, // no top-level directory
+		{Puzzle{2021, 3}, "2021/day03"},
+		{Puzzle{2023, 1}, "2023/day01"},
+	}
+	for _, tt := range tests {
+		if got := l.Dir(tt.p); got != filepath.Join(l.Root, tt.want) {
+			t.Errorf("Dir(%v) = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestYearsAndDays(t *testing.T) {
+	l := makeRoot(t)
+	if got := l.Years(); !reflect.DeepEqual(got, []int{2021, 2022}) {
+		t.Errorf("Years = %v", got)
+	}
+	if got := l.Days(2022); !reflect.DeepEqual(got, []Puzzle{{2022, 1}, {2022, 17}}) {
+		t.Errorf("Days(2022) = %v", got)
+	}
+	if got := l.Days(2021); !reflect.DeepEqual(got, []Puzzle{{2021, 3}}) {
+		t.Errorf("Days(2021) = %v", got)
+	}
+	if got := l.Days(2023); len(got) != 0 {
+		t.Errorf("Days(2023) = %v", got)
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	l := makeRoot(t)
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"day17", ModuleBase + "/day17"},
+		{"2023/day01", "aoc/2023/day01"},
+	}
+	for _, tt := range tests {
+		got, err := l.ModulePath(filepath.Join(l.Root, tt.dir))
+		if err != nil || got != tt.want {
+			t.Errorf("ModulePath(%s) = %s %v, want %s", tt.dir, got, err, tt.want)
+		}
+	}
+	if _, err := l.ModulePath(filepath.Dir(l.Root)); err == nil {
+		t.Error("ModulePath outside the root succeeded")
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	l := makeRoot(t)
+	p := Puzzle{2023, 1}
+	dir, err := l.Scaffold(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != l.Dir(p) {
+		t.Errorf("scaffolded %s, but Dir is %s", dir, l.Dir(p))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); err != nil {
+		t.Error("template main.go wasn't copied")
+	}
+	gomod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"module aoc/2023/day01\n",
+		"require " + LibModule + " v0.0.0\n",
+		"replace " + LibModule + " => ../../lib\n",
+	} {
+		if !strings.Contains(string(gomod), want) {
+			t.Errorf("go.mod is missing %q:\n%s", want, gomod)
+		}
+	}
+	if strings.Contains(string(gomod), "template") {
+		t.Errorf("template go.mod was copied:\n%s", gomod)
+	}
+	if got := l.Days(2023); !reflect.DeepEqual(got, []Puzzle{p}) {
+		t.Errorf("Days(2023) after scaffolding = %v", got)
+	}
+
+	if _, err := l.Scaffold(p); err == nil {
+		t.Error("scaffolding an existing day succeeded")
+	}
+	if _, err := l.Scaffold(Puzzle{2023, 26}); err == nil {
+		t.Error("scaffolding day 26 succeeded")
+	}
+}
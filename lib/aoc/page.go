@@ -0,0 +1,52 @@
+// Package aoc has the bookkeeping shared by the tools that work on a day's
+// directory: reading saved puzzle pages and the expected answers file.
+package aoc
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Part is what we can learn about one part of a puzzle from its description.
+type Part struct {
+	// Examples holds the text of each <pre><code> block, in order.
+	Examples []string
+	// Answer is the example answer: the last emphasized piece of code in
+	// the description, which is where the puzzle text states it.
+	Answer string
+}
+
+var (
+	articlePat = regexp.MustCompile(`(?s)<article[^>]*class="day-desc"[^>]*>(.*?)</article>`)
+	prePat     = regexp.MustCompile(`(?s)<pre><code>(.*?)</code></pre>`)
+	emphPat    = regexp.MustCompile(`(?s)<code><em>(.*?)</em></code>|<em><code>(.*?)</code></em>`)
+	tagPat     = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParsePage finds the puzzle descriptions in a saved puzzle page. There is one
+// part until the first star has been earned, and two after that.
+func ParsePage(page string) []Part {
+	var parts []Part
+	for _, article := range articlePat.FindAllStringSubmatch(page, -1) {
+		body := article[1]
+		var p Part
+		for _, m := range prePat.FindAllStringSubmatch(body, -1) {
+			p.Examples = append(p.Examples, text(m[1]))
+		}
+		// answers inside example blocks are just highlighting, not results
+		prose := prePat.ReplaceAllString(body, "")
+		ems := emphPat.FindAllStringSubmatch(prose, -1)
+		if len(ems) > 0 {
+			last := ems[len(ems)-1]
+			p.Answer = strings.TrimSpace(text(last[1] + last[2]))
+		}
+		parts = append(parts, p)
+	}
+	return parts
+}
+
+// strips tags (such as highlighting inside an example) and entities
+func text(s string) string {
+	return html.UnescapeString(tagPat.ReplaceAllString(s, ""))
+}
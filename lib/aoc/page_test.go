@@ -0,0 +1,71 @@
+package aoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readPage(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+const sampleStacks = "    [D]    \n[N] [C]    \n[Z] [M] [P]\n 1   2   3 \n"
+
+func TestParsePageOnePart(t *testing.T) {
+	parts := ParsePage(readPage(t, "part1.html"))
+	if len(parts) != 1 {
+		t.Fatalf("%d parts, want 1", len(parts))
+	}
+	p := parts[0]
+	if len(p.Examples) != 2 {
+		t.Fatalf("%d examples, want 2", len(p.Examples))
+	}
+	if want := sampleStacks + "\nmove 1 from 2 to 1\nmove 3 from 1 to 3\n"; p.Examples[0] != want {
+		t.Errorf("first example is %q, want %q", p.Examples[0], want)
+	}
+	// the highlighting inside the block is stripped
+	if want := "[Z] [M] [P]\n 1   2   3 \n"; p.Examples[1] != want {
+		t.Errorf("second example is %q, want %q", p.Examples[1], want)
+	}
+	// not the <em>Z</em> in the second example, nor the question in plain <em>
+	if p.Answer != "CMZ" {
+		t.Errorf("answer is %q, want CMZ", p.Answer)
+	}
+}
+
+func TestParsePageTwoParts(t *testing.T) {
+	parts := ParsePage(readPage(t, "part2.html"))
+	if len(parts) != 2 {
+		t.Fatalf("%d parts, want 2", len(parts))
+	}
+	if parts[0].Answer != "CMZ" || len(parts[0].Examples) != 1 || parts[0].Examples[0] != sampleStacks {
+		t.Errorf("part 1 is %+v", parts[0])
+	}
+	// <em><code> works as well as <code><em>, and the puzzle answers after
+	// each article aren't picked up
+	if parts[1].Answer != "MCD" {
+		t.Errorf("part 2 answer is %q, want MCD", parts[1].Answer)
+	}
+	if len(parts[1].Examples) != 1 || !strings.HasPrefix(parts[1].Examples[0], "[D]\n") {
+		t.Errorf("part 2 examples are %q", parts[1].Examples)
+	}
+}
+
+func TestParsePageEntities(t *testing.T) {
+	page := `<article class="day-desc"><pre><code>a &lt; b &amp;&amp; c
+</code></pre><p>The answer is <code><em>&gt;=3</em></code>.</p></article>`
+	parts := ParsePage(page)
+	if len(parts) != 1 || parts[0].Examples[0] != "a < b && c\n" || parts[0].Answer != ">=3" {
+		t.Errorf("got %+v", parts)
+	}
+	if parts := ParsePage("<html><body>no puzzle here</body></html>"); len(parts) != 0 {
+		t.Errorf("found %d parts in a page without any", len(parts))
+	}
+}
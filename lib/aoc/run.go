@@ -0,0 +1,151 @@
+package aoc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Solver is a day's program, compiled so that it can be run repeatedly.
+type Solver struct {
+	Dir string
+	bin string
+	tmp string
+}
+
+// Build compiles the day in dir. Call Close to remove the binary.
+func Build(dir string) (*Solver, error) {
+	tmp, err := os.MkdirTemp("", "aoc-build")
+	if err != nil {
+		return nil, err
+	}
+	s := &Solver{Dir: dir, bin: filepath.Join(tmp, "solver"), tmp: tmp}
+	cmd := exec.Command("go", "build", "-o", s.bin, ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("build failed: %w\n%s", err, out)
+	}
+	return s, nil
+}
+
+func (s *Solver) Close() error {
+	return os.RemoveAll(s.tmp)
+}
+
+// Result is the outcome of running a solver on one input.
+type Result struct {
+	Input    string
+	Output   string   // everything written to stdout
+	Stderr   string   // everything written to stderr
+	Answers  []string // the last lines of output, one per part
+	Duration time.Duration
+	Err      error
+	Panicked bool
+	TimedOut bool
+}
+
+// Failed reports whether the run didn't finish cleanly.
+func (r *Result) Failed() bool {
+	return r.Err != nil
+}
+
+// Answer returns the answer for part (1 or 2), or "" if there isn't one.
+func (r *Result) Answer(part int) string {
+	if part < 1 || part > len(r.Answers) {
+		return ""
+	}
+	return r.Answers[part-1]
+}
+
+// Run runs the solver on the named input file. The solvers all open their
+// input by a fixed name (usually ./input.txt, sometimes ./inputsample.txt), so
+// the solver runs in a scratch directory where every input*.txt name from the
+// day's directory holds the contents of the chosen input.
+//
+// The last nparts nonblank lines of output are taken as the answers, since
+// the solvers print their answers last, after any debugging output.
+func (s *Solver) Run(input string, nparts int, timeout time.Duration) Result {
+	res := Result{Input: input}
+	data, err := os.ReadFile(input)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	work, err := os.MkdirTemp(s.tmp, "run")
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer os.RemoveAll(work)
+	names, _ := filepath.Glob(filepath.Join(s.Dir, "input*.txt"))
+	names = append(names, "input.txt")
+	for _, n := range names {
+		if err := os.WriteFile(filepath.Join(work, filepath.Base(n)), data, 0644); err != nil {
+			res.Err = err
+			return res
+		}
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, s.bin)
+	cmd.Dir = work
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	start := time.Now()
+	err = cmd.Run()
+	res.Duration = time.Since(start)
+	res.Output = stdout.String()
+	res.Stderr = stderr.String()
+	res.Answers = lastLines(res.Output, nparts)
+
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		res.TimedOut = true
+		res.Err = fmt.Errorf("timed out after %v", timeout)
+	case err != nil:
+		res.Panicked = strings.Contains(res.Stderr, "panic:")
+		var exit *exec.ExitError
+		if res.Panicked {
+			res.Err = errors.New(panicMessage(res.Stderr))
+		} else if errors.As(err, &exit) {
+			res.Err = fmt.Errorf("exit status %d: %s", exit.ExitCode(), firstLine(res.Stderr))
+		} else {
+			res.Err = err
+		}
+	}
+	return res
+}
+
+func lastLines(s string, n int) []string {
+	var lines []string
+	all := strings.Split(s, "\n")
+	for i := len(all) - 1; i >= 0 && len(lines) < n; i-- {
+		if l := strings.TrimSpace(all[i]); l != "" {
+			lines = append([]string{l}, lines...)
+		}
+	}
+	return lines
+}
+
+func firstLine(s string) string {
+	l, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return l
+}
+
+func panicMessage(stderr string) string {
+	ix := strings.Index(stderr, "panic:")
+	return firstLine(stderr[ix:])
+}
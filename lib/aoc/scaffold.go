@@ -0,0 +1,59 @@
+package aoc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Scaffold creates the directory for a new day from the _template directory,
+// with a go.mod whose module path matches the directory and which can import
+// the shared libraries in lib. It returns the new directory.
+func (l Layout) Scaffold(p Puzzle) (string, error) {
+	if err := p.Valid(); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(l.YearDir(p.Year), p.DirName())
+	if _, err := os.Stat(dir); err == nil {
+		return "", fmt.Errorf("%s already exists", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	tmpl := filepath.Join(l.Root, "_template")
+	entries, err := os.ReadDir(tmpl)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == "go.mod" || e.Name() == "go.sum" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(tmpl, e.Name()))
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(dir, e.Name()), b, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	modpath, err := l.ModulePath(dir)
+	if err != nil {
+		return "", err
+	}
+	libRel, err := filepath.Rel(dir, filepath.Join(l.Root, "lib"))
+	if err != nil {
+		return "", err
+	}
+	var gomod strings.Builder
+	fmt.Fprintf(&gomod, "module %s\n\ngo 1.19\n\n", modpath)
+	fmt.Fprintf(&gomod, "require %s v0.0.0\n\n", LibModule)
+	fmt.Fprintf(&gomod, "replace %s => %s\n", LibModule, filepath.ToSlash(libRel))
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod.String()), 0644); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
@@ -0,0 +1,219 @@
+// Package asciicast records terminal output into asciicast v2 files, the
+// format used by asciinema, so that an animation can be replayed and shared
+// without rerunning the simulation that produced it.
+//
+// A Recorder is an io.Writer; point a term.Screen or plain fmt.Fprint calls
+// at it, and call Frame between frames. Timestamps come from a virtual clock
+// that advances by FrameDelay at each Frame, so recording runs at full speed
+// and the delay is a setting rather than a time.Sleep in the solver.
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+type Recorder struct {
+	// FrameDelay is how far the clock moves at each call to Frame.
+	FrameDelay time.Duration
+	// If Echo is set, output is also written to it as it is recorded, and
+	// Frame sleeps for FrameDelay so that the live view runs at the same pace
+	// as the replay will.
+	Echo io.Writer
+
+	out     *bufio.Writer
+	elapsed time.Duration
+	err     error
+	partial []byte // an incomplete UTF-8 sequence at the end of the last Write
+}
+
+// NewRecorder writes the header for a width by height terminal to w and
+// returns a recorder for the rest of the file.
+func NewRecorder(w io.Writer, width, height int, title string) (*Recorder, error) {
+	r := &Recorder{
+		FrameDelay: 200 * time.Millisecond,
+		out:        bufio.NewWriter(w),
+	}
+	hdr := Header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: time.Now().Unix(),
+		Title:     title,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	}
+	b, err := json.Marshal(hdr)
+	if err != nil {
+		return nil, err
+	}
+	r.out.Write(b)
+	r.out.WriteByte('\n')
+	return r, nil
+}
+
+// Write records p as output at the current time. Writers such as a buffered
+// term.Screen can split a multi-byte character across two writes, so an
+// incomplete one at the end of p is held back and recorded with the next
+// write.
+func (r *Recorder) Write(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.Echo != nil {
+		r.Echo.Write(p)
+	}
+	data := append(r.partial, p...)
+	cut := incomplete(data)
+	r.partial = append([]byte(nil), data[cut:]...)
+	if err := r.event(data[:cut]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// incomplete returns where a trailing incomplete UTF-8 sequence starts in p,
+// or len(p) if there isn't one.
+func incomplete(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(p)
+}
+
+// event writes an output event for data, if there is any
+func (r *Recorder) event(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	b, err := json.Marshal([]any{r.elapsed.Seconds(), "o", string(data)})
+	if err != nil {
+		r.err = err
+		return err
+	}
+	r.out.Write(b)
+	if err := r.out.WriteByte('\n'); err != nil {
+		r.err = err
+		return err
+	}
+	return nil
+}
+
+// Frame marks the end of a frame and moves the clock on by FrameDelay.
+func (r *Recorder) Frame() {
+	r.elapsed += r.FrameDelay
+	if r.Echo != nil {
+		time.Sleep(r.FrameDelay)
+	}
+}
+
+// Pause moves the clock on by d without ending a frame, e.g. to hold the last
+// frame on screen for a while.
+func (r *Recorder) Pause(d time.Duration) {
+	r.elapsed += d
+}
+
+// Elapsed is the current time on the recording's clock.
+func (r *Recorder) Elapsed() time.Duration {
+	return r.elapsed
+}
+
+// Close flushes the recording. It does not close the underlying writer.
+func (r *Recorder) Close() error {
+	if r.err != nil {
+		return r.err
+	}
+	// whatever is left can't be completed now
+	if err := r.event(r.partial); err != nil {
+		return err
+	}
+	r.partial = nil
+	return r.out.Flush()
+}
+
+// Create records to a new file; closing the returned recorder closes the file.
+func Create(filename string, width, height int, title string) (*FileRecorder, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewRecorder(f, width, height, title)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &FileRecorder{Recorder: r, f: f}, nil
+}
+
+type FileRecorder struct {
+	*Recorder
+	f *os.File
+}
+
+func (fr *FileRecorder) Close() error {
+	err := fr.Recorder.Close()
+	if cerr := fr.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Play replays a recording to w, sleeping between events. Speed 2 plays twice
+// as fast; speed 0 or less skips the sleeping altogether.
+func Play(cast io.Reader, w io.Writer, speed float64) (Header, error) {
+	var hdr Header
+	scanner := bufio.NewScanner(cast)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		return hdr, fmt.Errorf("asciicast: missing header")
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &hdr); err != nil {
+		return hdr, fmt.Errorf("asciicast: bad header: %w", err)
+	}
+	if hdr.Version != 2 {
+		return hdr, fmt.Errorf("asciicast: unsupported version %d", hdr.Version)
+	}
+	var last float64
+	for scanner.Scan() {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return hdr, fmt.Errorf("asciicast: bad event: %w", err)
+		}
+		if len(event) != 3 {
+			return hdr, fmt.Errorf("asciicast: bad event %s", scanner.Text())
+		}
+		t, _ := event[0].(float64)
+		kind, _ := event[1].(string)
+		data, _ := event[2].(string)
+		if kind != "o" {
+			continue
+		}
+		if speed > 0 && t > last {
+			time.Sleep(time.Duration((t - last) / speed * float64(time.Second)))
+		}
+		last = t
+		if _, err := io.WriteString(w, data); err != nil {
+			return hdr, err
+		}
+	}
+	return hdr, scanner.Err()
+}
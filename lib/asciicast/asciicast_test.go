@@ -0,0 +1,84 @@
+package asciicast
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// records the writes and returns the output events
+func record(t *testing.T, writes ...[]byte) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	r, err := NewRecorder(&buf, 10, 2, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range writes {
+		if n, err := r.Write(w); err != nil || n != len(w) {
+			t.Fatalf("Write returned %d, %v", n, err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var out []string
+	for _, l := range lines[1:] {
+		var event []any
+		if err := json.Unmarshal([]byte(l), &event); err != nil {
+			t.Fatalf("bad event %q: %v", l, err)
+		}
+		out = append(out, event[2].(string))
+	}
+	return out
+}
+
+func TestSplitRune(t *testing.T) {
+	s := []byte("a█b") // the block is 3 bytes
+	for split := 1; split < len(s); split++ {
+		events := record(t, s[:split], s[split:])
+		if got := strings.Join(events, ""); got != "a█b" {
+			t.Errorf("split at %d: recorded %q", split, got)
+		}
+		for _, e := range events {
+			if strings.ContainsRune(e, '�') {
+				t.Errorf("split at %d: event %q has a replacement character", split, e)
+			}
+		}
+	}
+}
+
+func TestSplitRuneThreeWays(t *testing.T) {
+	s := []byte("😀")
+	events := record(t, s[:1], s[1:3], s[3:])
+	if len(events) != 1 || events[0] != "😀" {
+		t.Errorf("recorded %q", events)
+	}
+}
+
+func TestIncompleteAtClose(t *testing.T) {
+	s := []byte("x█")
+	events := record(t, s[:2])
+	if len(events) != 2 || events[0] != "x" {
+		t.Errorf("recorded %q", events)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	var buf bytes.Buffer
+	r, _ := NewRecorder(&buf, 10, 2, "test")
+	r.Write([]byte("one "))
+	r.Frame()
+	r.Write([]byte("two"))
+	r.Close()
+	var out bytes.Buffer
+	hdr, err := Play(&buf, &out, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hdr.Width != 10 || hdr.Title != "test" || out.String() != "one two" {
+		t.Errorf("played %+v %q", hdr, out.String())
+	}
+}
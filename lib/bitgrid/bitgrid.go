@@ -0,0 +1,283 @@
+// Package bitgrid is a tall grid of on/off cells with each row packed into
+// uint64 words, generalizing day 17's RockSpace (which fits a row in a byte
+// and so can't be wider than 8). Sprites are shifted into place a word at a
+// time, so collision tests and placement cost a few machine operations per
+// row whatever the width.
+//
+// Rows are numbered from the bottom up, starting at 0, and cells within a row
+// from the left, starting at 0. Rows that can no longer matter can be trimmed
+// off the bottom to keep memory bounded on very long runs; row numbers don't
+// change when that happens.
+package bitgrid
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// Sprite is a shape that can be placed in a Grid. Rows[0] is the bottom row.
+type Sprite struct {
+	Width  int
+	Height int
+	Rows   [][]uint64
+
+	narrow []uint64 // Rows[y][0] for each row, if the sprite fits in one word
+}
+
+// NewSprite builds a sprite from lines of '.' and '#', given bottom row first
+// as day 17 does. All lines should be the same length.
+func NewSprite(lines ...string) Sprite {
+	s := Sprite{Height: len(lines)}
+	for _, l := range lines {
+		if len(l) > s.Width {
+			s.Width = len(l)
+		}
+	}
+	for _, l := range lines {
+		row := make([]uint64, wordsFor(s.Width))
+		for x, ch := range l {
+			if ch == '#' {
+				row[x/64] |= 1 << (x % 64)
+			}
+		}
+		s.Rows = append(s.Rows, row)
+	}
+	if s.Width <= 64 {
+		for _, row := range s.Rows {
+			s.narrow = append(s.narrow, row[0])
+		}
+	}
+	return s
+}
+
+func wordsFor(width int) int {
+	return (width + 63) / 64
+}
+
+// Grid is a fixed-width, unbounded-height grid.
+type Grid struct {
+	Width int
+
+	words  int      // words per row
+	cells  []uint64 // rows from Base upwards, words per row each
+	base   int      // number of the lowest row still stored
+	height int      // one more than the highest row with anything in it
+}
+
+func New(width int) *Grid {
+	return &Grid{Width: width, words: wordsFor(width)}
+}
+
+// Height returns one more than the number of the highest nonempty row, or the
+// base if nothing above it has been filled.
+func (g *Grid) Height() int {
+	return g.height
+}
+
+// Base returns the number of the lowest row that hasn't been trimmed.
+func (g *Grid) Base() int {
+	return g.base
+}
+
+// row returns the words of row y, or nil if it is not stored
+func (g *Grid) row(y int) []uint64 {
+	i := (y - g.base) * g.words
+	if y < g.base || i >= len(g.cells) {
+		return nil
+	}
+	return g.cells[i : i+g.words]
+}
+
+func (g *Grid) grow(y int) {
+	need := (y - g.base + 1) * g.words
+	if need <= len(g.cells) {
+		return
+	}
+	if need > cap(g.cells) {
+		g.cells = append(g.cells, make([]uint64, need-len(g.cells))...)
+		return
+	}
+	// reuse the space left by trimming, which may still hold old rows
+	old := len(g.cells)
+	g.cells = g.cells[:need]
+	for i := old; i < need; i++ {
+		g.cells[i] = 0
+	}
+}
+
+// Get reports whether cell x, y is filled. Cells outside the grid are empty,
+// except for trimmed rows, which count as filled.
+func (g *Grid) Get(x, y int) bool {
+	if y < g.base {
+		return true
+	}
+	if x < 0 || x >= g.Width {
+		return false
+	}
+	r := g.row(y)
+	return r != nil && r[x/64]&(1<<(x%64)) != 0
+}
+
+func (g *Grid) Set(x, y int) {
+	if x < 0 || x >= g.Width || y < g.base {
+		return
+	}
+	g.grow(y)
+	g.row(y)[x/64] |= 1 << (x % 64)
+	if y >= g.height {
+		g.height = y + 1
+	}
+}
+
+// RowWords returns the packed contents of row y, least significant bit first.
+// The slice belongs to the grid. Rows above the top are nil.
+func (g *Grid) RowWords(y int) []uint64 {
+	return g.row(y)
+}
+
+// shifted returns word i of a sprite row moved x cells to the right
+func shifted(row []uint64, x int, i int) uint64 {
+	wordShift, bitShift := x/64, x%64
+	j := i - wordShift
+	var w uint64
+	if j >= 0 && j < len(row) {
+		w = row[j] << bitShift
+	}
+	if bitShift != 0 && j-1 >= 0 && j-1 < len(row) {
+		w |= row[j-1] >> (64 - bitShift)
+	}
+	return w
+}
+
+// Collides reports whether sprite s with its bottom left corner at x, y would
+// overlap a wall, the floor (including trimmed rows), or a filled cell.
+func (g *Grid) Collides(s Sprite, x, y int) bool {
+	if x < 0 || x+s.Width > g.Width || y < g.base {
+		return true
+	}
+	if y >= g.height {
+		return false
+	}
+	if g.words == 1 && s.narrow != nil {
+		// the common narrow case, where nothing needs to cross a word
+		cells := g.cells[y-g.base:]
+		for sy, w := range s.narrow {
+			if sy >= len(cells) {
+				return false
+			}
+			if w<<x&cells[sy] != 0 {
+				return true
+			}
+		}
+		return false
+	}
+	for sy := 0; sy < s.Height; sy++ {
+		r := g.row(y + sy)
+		if r == nil {
+			return false
+		}
+		for i := range r {
+			if shifted(s.Rows[sy], x, i)&r[i] != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Place fills the cells covered by sprite s with its bottom left corner at x,
+// y. It doesn't check for collisions.
+func (g *Grid) Place(s Sprite, x, y int) {
+	if y < g.base {
+		return
+	}
+	g.grow(y + s.Height - 1)
+	if g.words == 1 && s.narrow != nil {
+		cells := g.cells[y-g.base:]
+		for sy, w := range s.narrow {
+			cells[sy] |= w << x
+			if w != 0 && y+sy >= g.height {
+				g.height = y + sy + 1
+			}
+		}
+		return
+	}
+	for sy := 0; sy < s.Height; sy++ {
+		r := g.row(y + sy)
+		filled := false
+		for i := range r {
+			w := shifted(s.Rows[sy], x, i)
+			r[i] |= w
+			filled = filled || w != 0
+		}
+		if filled && y+sy >= g.height {
+			g.height = y + sy + 1
+		}
+	}
+}
+
+// Count returns the number of filled cells in row y.
+func (g *Grid) Count(y int) int {
+	n := 0
+	for _, w := range g.row(y) {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Full reports whether every cell in row y is filled.
+func (g *Grid) Full(y int) bool {
+	return y < g.base || g.Count(y) == g.Width
+}
+
+// TrimBelow discards every row below y. Afterwards those rows behave as if
+// they were solid.
+func (g *Grid) TrimBelow(y int) {
+	if y <= g.base {
+		return
+	}
+	n := (y - g.base) * g.words
+	if n >= len(g.cells) {
+		g.cells = g.cells[:0]
+	} else {
+		g.cells = append(g.cells[:0], g.cells[n:]...)
+	}
+	g.base = y
+	if g.height < y {
+		g.height = y
+	}
+}
+
+// Trim keeps only the top keep rows below the height.
+func (g *Grid) Trim(keep int) {
+	g.TrimBelow(g.height - keep)
+}
+
+// TrimFull discards everything below the highest full row, since nothing can
+// get past it.
+func (g *Grid) TrimFull() {
+	for y := g.height - 1; y > g.base; y-- {
+		if g.Full(y) {
+			g.TrimBelow(y)
+			return
+		}
+	}
+}
+
+// String draws the stored rows top down, like day 17's Print.
+func (g *Grid) String() string {
+	var b strings.Builder
+	for y := g.height - 1; y >= g.base; y-- {
+		for x := 0; x < g.Width; x++ {
+			if g.Get(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString(strings.Repeat("-", g.Width))
+	b.WriteByte('\n')
+	return b.String()
+}
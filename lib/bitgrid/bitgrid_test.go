@@ -0,0 +1,263 @@
+package bitgrid
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestShiftedAcrossWords(t *testing.T) {
+	g := New(70)
+	s := NewSprite("####")
+	if g.Collides(s, 67, 0) != true {
+		t.Error("sprite past the right wall should collide")
+	}
+	if g.Collides(s, 62, 0) {
+		t.Error("empty grid shouldn't collide")
+	}
+	g.Place(s, 62, 0)
+	for x := 0; x < 70; x++ {
+		want := x >= 62 && x < 66
+		if g.Get(x, 0) != want {
+			t.Errorf("cell %d is %v, want %v", x, g.Get(x, 0), want)
+		}
+	}
+	r := g.RowWords(0)
+	if r[0] != 3<<62 || r[1] != 3 {
+		t.Errorf("row words are %x %x", r[0], r[1])
+	}
+	if g.Count(0) != 4 {
+		t.Errorf("count is %d, want 4", g.Count(0))
+	}
+
+	for x := 60; x < 68; x++ {
+		want := x >= 62 && x < 66
+		if got := g.Collides(NewSprite("#"), x, 0); got != want {
+			t.Errorf("single cell at %d: collides is %v, want %v", x, got, want)
+		}
+	}
+	if !g.Collides(NewSprite("#..#"), 62, 0) || g.Collides(NewSprite("#"), 66, 0) {
+		t.Error("wrong collision just past the placed sprite")
+	}
+	if g.Collides(NewSprite("#.....#"), 61, 0) {
+		t.Error("sprite straddling the placed one shouldn't collide")
+	}
+	if !g.Collides(NewSprite("#.....#"), 59, 0) {
+		t.Error("sprite whose second cell lands at 65 should collide")
+	}
+}
+
+func TestWideSprite(t *testing.T) {
+	// a sprite more than one word wide, shifted by a non-multiple of 64
+	line := "#" + strings.Repeat(".", 68) + "#"
+	s := NewSprite(line)
+	g := New(200)
+	g.Place(s, 100, 3)
+	if !g.Get(100, 3) || !g.Get(169, 3) || g.Count(3) != 2 {
+		t.Errorf("wide sprite placed wrong:\n%s", g)
+	}
+	if !g.Collides(NewSprite("#"), 169, 3) || g.Collides(NewSprite("#"), 168, 3) {
+		t.Error("wrong collision with wide sprite")
+	}
+	if g.Height() != 4 {
+		t.Errorf("height is %d, want 4", g.Height())
+	}
+}
+
+func TestTrim(t *testing.T) {
+	g := New(5)
+	for y := 0; y < 10; y++ {
+		g.Set(y%5, y)
+	}
+	g.TrimBelow(4)
+	if g.Base() != 4 || g.Height() != 10 {
+		t.Fatalf("base %d height %d after trim", g.Base(), g.Height())
+	}
+	if !g.Get(0, 3) || !g.Collides(NewSprite("#"), 0, 3) {
+		t.Error("trimmed rows should count as filled")
+	}
+	if !g.Get(4, 4) || g.Get(3, 4) || !g.Get(0, 5) {
+		t.Error("rows above the trim moved")
+	}
+	g.TrimBelow(2)
+	if g.Base() != 4 {
+		t.Error("trimming lower than the base changed it")
+	}
+
+	g.Trim(3)
+	if g.Base() != 7 || !g.Get(2, 7) {
+		t.Errorf("Trim(3) left base %d", g.Base())
+	}
+
+	g.TrimBelow(20)
+	if g.Base() != 20 || g.Height() != 20 {
+		t.Errorf("trimming above the top left base %d height %d", g.Base(), g.Height())
+	}
+}
+
+func TestTrimFull(t *testing.T) {
+	g := New(4)
+	g.Place(NewSprite("####"), 0, 2)
+	g.Place(NewSprite("####"), 0, 5)
+	g.Set(1, 6)
+	g.Set(0, 0)
+	g.TrimFull()
+	if g.Base() != 5 {
+		t.Fatalf("base is %d, want 5", g.Base())
+	}
+	if !g.Get(1, 6) || g.Get(0, 6) {
+		t.Error("row above the full row changed")
+	}
+	// the highest full row is the base itself, so nothing more goes
+	g.TrimFull()
+	if g.Base() != 5 {
+		t.Errorf("second TrimFull moved base to %d", g.Base())
+	}
+}
+
+// rockSpace is a copy of day 17's byte-per-row RockSpace, to compare against.
+type rockSpace struct {
+	Contents []byte
+	Width    int
+}
+
+func toBinary(s string) byte {
+	r := strings.NewReplacer(".", "0", "#", "1")
+	b, _ := strconv.ParseUint(r.Replace(s), 2, 8)
+	return byte(b << (8 - len(s)))
+}
+
+func rs(a ...string) rockSpace {
+	r := rockSpace{Width: len(a[0])}
+	for _, s := range a {
+		r.Contents = append(r.Contents, toBinary(s))
+	}
+	return r
+}
+
+func (r *rockSpace) Height() int {
+	for i := len(r.Contents) - 1; i >= 0; i-- {
+		if r.Contents[i] != 0 {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (r *rockSpace) Collides(rock rockSpace, x int, y int) bool {
+	if x < 0 || y < 0 || x+rock.Width > r.Width {
+		return true
+	}
+	for rockY := 0; rockY < len(rock.Contents); rockY++ {
+		spaceY := y + rockY
+		if spaceY >= len(r.Contents) {
+			return false
+		}
+		if (rock.Contents[rockY]>>x)&r.Contents[spaceY] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rockSpace) Place(rock rockSpace, x int, y int) {
+	for y+len(rock.Contents) >= len(r.Contents) {
+		r.Contents = append(r.Contents, 0)
+	}
+	for rockY := 0; rockY < len(rock.Contents); rockY++ {
+		r.Contents[y+rockY] |= rock.Contents[rockY] >> x
+	}
+}
+
+const wind = ">>><<><>><<<>><>>><<<>>><<<><<<>><>><<>>"
+
+var shapes = [][]string{
+	{"####"},
+	{".#.", "###", ".#."},
+	{"###", "..#", "..#"},
+	{"#", "#", "#", "#"},
+	{"##", "##"},
+}
+
+// drop runs day 17's simulation for n rocks and returns the height, using
+// collides and place on whichever representation of the chamber
+func drop(n int, height func() int, collides func(rock, x, y int) bool, place func(rock, x, y int)) int {
+	windIx := 0
+	for count := 0; count < n; count++ {
+		rock := count % len(shapes)
+		x, y := 2, height()+3
+		for {
+			dx := 1
+			if wind[windIx] == '<' {
+				dx = -1
+			}
+			windIx = (windIx + 1) % len(wind)
+			if !collides(rock, x+dx, y) {
+				x += dx
+			}
+			if collides(rock, x, y-1) {
+				place(rock, x, y)
+				break
+			}
+			y--
+		}
+	}
+	return height()
+}
+
+func dropGrid(n int, trim bool) int {
+	sprites := make([]Sprite, len(shapes))
+	for i, s := range shapes {
+		sprites[i] = NewSprite(s...)
+	}
+	g := New(7)
+	return drop(n, g.Height,
+		func(rock, x, y int) bool { return g.Collides(sprites[rock], x, y) },
+		func(rock, x, y int) {
+			g.Place(sprites[rock], x, y)
+			if trim {
+				g.Trim(64)
+			}
+		})
+}
+
+func dropRockSpace(n int) int {
+	rocks := make([]rockSpace, len(shapes))
+	for i, s := range shapes {
+		rocks[i] = rs(s...)
+	}
+	chamber := rockSpace{Width: 7}
+	return drop(n, chamber.Height,
+		func(rock, x, y int) bool { return chamber.Collides(rocks[rock], x, y) },
+		func(rock, x, y int) { chamber.Place(rocks[rock], x, y) })
+}
+
+func TestDay17Sample(t *testing.T) {
+	for name, got := range map[string]int{
+		"grid":         dropGrid(2022, false),
+		"trimmed grid": dropGrid(2022, true),
+		"rockspace":    dropRockSpace(2022),
+	} {
+		if got != 3068 {
+			t.Errorf("%s: height %d, want 3068", name, got)
+		}
+	}
+}
+
+func BenchmarkDropGrid(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		dropGrid(2022, false)
+	}
+}
+
+func BenchmarkDropGridTrimmed(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		dropGrid(2022, true)
+	}
+}
+
+func BenchmarkDropRockSpace(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		dropRockSpace(2022)
+	}
+}
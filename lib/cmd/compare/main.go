@@ -0,0 +1,128 @@
+// Command compare runs a day's solver against every input file in a directory,
+// such as a collection of the team's puzzle inputs, and prints a table of the
+// answers and timings. Inputs where the solver errors, panics or takes longer
+// than the budget are flagged, as are answers that disagree with an
+// expected.txt in the inputs directory.
+//
+//	go run ./cmd/compare --year 2022 --day 15 -inputs ~/aoc-inputs/2022/day15 -budget 5s
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kentquirk/aoc2022/lib/aoc"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "day directory")
+	year := flag.Int("year", aoc.LegacyYear, "puzzle year, used with -day")
+	day := flag.Int("day", 0, "puzzle day; selects the directory instead of -dir")
+	inputs := flag.String("inputs", "", "directory of input files (default: the day directory)")
+	pattern := flag.String("pattern", "*.txt", "glob for input files within -inputs")
+	budget := flag.Duration("budget", 15*time.Second, "flag runs that take longer than this")
+	timeout := flag.Duration("timeout", 2*time.Minute, "kill runs that take longer than this")
+	jobs := flag.Int("j", 1, "number of inputs to run at once")
+	flag.Parse()
+
+	d, err := aoc.ResolveDir(*dir, *year, *day)
+	if err != nil {
+		log.Fatal(err)
+	}
+	*dir = d
+
+	if *inputs == "" {
+		*inputs = *dir
+	}
+	files, err := filepath.Glob(filepath.Join(*inputs, *pattern))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		if filepath.Base(f) != aoc.ExpectedFile {
+			names = append(names, f)
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		log.Fatalf("no files matching %s in %s", *pattern, *inputs)
+	}
+	expected, err := aoc.ReadExpected(*inputs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	solver, err := aoc.Build(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer solver.Close()
+
+	results := make([]aoc.Result, len(names))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(*jobs, 1))
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			results[i] = solver.Run(name, 2, *timeout)
+			<-sem
+		}(i, name)
+	}
+	wg.Wait()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "INPUT\tPART 1\tPART 2\tTIME\tPROBLEMS")
+	flagged := 0
+	for _, r := range results {
+		name := filepath.Base(r.Input)
+		var problems []string
+		switch {
+		case r.TimedOut:
+			problems = append(problems, "TIMEOUT")
+		case r.Panicked:
+			problems = append(problems, "PANIC: "+r.Err.Error())
+		case r.Failed():
+			problems = append(problems, "ERROR: "+r.Err.Error())
+		}
+		if !r.TimedOut && r.Duration > *budget {
+			problems = append(problems, "SLOW")
+		}
+		answers := []string{"-", "-"}
+		if !r.Failed() {
+			for p := 1; p <= 2; p++ {
+				answers[p-1] = r.Answer(p)
+				if want, ok := expected.Get(name, p); ok && want != r.Answer(p) {
+					problems = append(problems, fmt.Sprintf("part %d WRONG, want %s", p, want))
+				}
+			}
+		}
+		if len(problems) > 0 {
+			flagged++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%s\n", name, answers[0], answers[1],
+			r.Duration.Round(time.Millisecond), strings.Join(problems, "; "))
+	}
+	tw.Flush()
+	fmt.Printf("%d inputs, %d flagged\n", len(results), flagged)
+	if flagged > 0 {
+		os.Exit(1)
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,30 @@
+// Command newday creates the directory for a day's puzzle from _template, in
+// the year directory, set up to import the shared libraries.
+//
+//	go run ./cmd/newday --year 2023 --day 1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kentquirk/aoc2022/lib/aoc"
+)
+
+func main() {
+	year := flag.Int("year", time.Now().Year(), "puzzle year")
+	day := flag.Int("day", 0, "puzzle day")
+	flag.Parse()
+
+	l, err := aoc.FindLayout(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	dir, err := l.Scaffold(aoc.Puzzle{Year: *year, Day: *day})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(dir)
+}
@@ -0,0 +1,64 @@
+// Command run builds and runs one day's solver, chosen by year and day, on its
+// real or sample input.
+//
+//	go run ./cmd/run --year 2022 --day 17 --input inputsample.txt
+//	go run ./cmd/run --year 2022 --list
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kentquirk/aoc2022/lib/aoc"
+)
+
+func main() {
+	year := flag.Int("year", aoc.LegacyYear, "puzzle year")
+	day := flag.Int("day", 0, "puzzle day")
+	input := flag.String("input", "input.txt", "input file within the day's directory")
+	timeout := flag.Duration("timeout", 0, "time limit for the run (0 for none)")
+	list := flag.Bool("list", false, "list the days that exist for the year (or every year, with -year 0)")
+	flag.Parse()
+
+	l, err := aoc.FindLayout(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *list {
+		years := []int{*year}
+		if *year == 0 {
+			years = l.Years()
+		}
+		for _, y := range years {
+			for _, p := range l.Days(y) {
+				fmt.Printf("%v\t%s\n", p, l.Dir(p))
+			}
+		}
+		return
+	}
+
+	dir, err := aoc.ResolveDir("", *year, *day)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if dir == "" {
+		log.Fatal("--day is required")
+	}
+	solver, err := aoc.Build(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer solver.Close()
+
+	res := solver.Run(filepath.Join(dir, *input), 2, *timeout)
+	fmt.Print(res.Output)
+	fmt.Fprint(os.Stderr, res.Stderr)
+	fmt.Fprintf(os.Stderr, "%v on %s: %v\n", aoc.Puzzle{Year: *year, Day: *day}, *input, res.Duration.Round(time.Millisecond))
+	if res.Failed() {
+		log.Fatal(res.Err)
+	}
+}
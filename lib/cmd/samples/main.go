@@ -0,0 +1,104 @@
+// Command samples pulls the example input and example answers out of a puzzle
+// page saved from the browser, and writes them into a day's directory as
+// inputsample.txt (plus inputsample2.txt and so on if asked for more blocks)
+// and expected.txt.
+//
+//	go run ./cmd/samples -page ~/Downloads/day07.html -dir ../day07
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kentquirk/aoc2022/lib/aoc"
+)
+
+func sampleName(n int) string {
+	if n == 1 {
+		return "inputsample.txt"
+	}
+	return fmt.Sprintf("inputsample%d.txt", n)
+}
+
+func main() {
+	page := flag.String("page", "", "saved puzzle page (HTML)")
+	dir := flag.String("dir", ".", "day directory to write into")
+	blocks := flag.String("blocks", "1", "comma-separated example block numbers to save, counting from 1 across both parts")
+	list := flag.Bool("list", false, "print the example blocks and answers instead of writing files")
+	force := flag.Bool("force", false, "overwrite existing sample files")
+	flag.Parse()
+
+	if *page == "" {
+		log.Fatal("-page is required")
+	}
+	b, err := os.ReadFile(*page)
+	if err != nil {
+		log.Fatal(err)
+	}
+	parts := aoc.ParsePage(string(b))
+	if len(parts) == 0 {
+		log.Fatal("no puzzle description found in ", *page)
+	}
+
+	var examples []string
+	for _, p := range parts {
+		examples = append(examples, p.Examples...)
+	}
+
+	if *list {
+		for i, ex := range examples {
+			fmt.Printf("---- block %d ----\n%s", i+1, ex)
+		}
+		for i, p := range parts {
+			fmt.Printf("---- part %d answer: %s\n", i+1, p.Answer)
+		}
+		return
+	}
+
+	var chosen []int
+	for _, s := range strings.Split(*blocks, ",") {
+		var n int
+		if _, err := fmt.Sscanf(strings.TrimSpace(s), "%d", &n); err != nil || n < 1 || n > len(examples) {
+			log.Fatalf("bad block number %q; the page has %d blocks", s, len(examples))
+		}
+		chosen = append(chosen, n)
+	}
+
+	for i, n := range chosen {
+		name := filepath.Join(*dir, sampleName(i+1))
+		if _, err := os.Stat(name); err == nil && !*force {
+			log.Fatalf("%s already exists; use -force to replace it", name)
+		}
+		// the solvers split on newlines, so leave off the final one
+		sample := strings.TrimSuffix(examples[n-1], "\n")
+		if err := os.WriteFile(name, []byte(sample), 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("wrote", name)
+	}
+
+	exp, err := aoc.ReadExpected(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, p := range parts {
+		if p.Answer == "" {
+			continue
+		}
+		// part 2 sometimes has its own example; if we saved one, that's
+		// what its answer refers to
+		input := sampleName(1)
+		if i == 1 && len(chosen) > 1 {
+			input = sampleName(2)
+		}
+		exp.Set(input, i+1, p.Answer)
+		fmt.Printf("%s part%d: %s\n", input, i+1, p.Answer)
+	}
+	if err := exp.Write(*dir); err != nil {
+		log.Fatal(err)
+	}
+}
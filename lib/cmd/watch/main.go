@@ -0,0 +1,131 @@
+// Command watch reruns a day's solver whenever its code or input changes. It
+// polls the day's directory for changes to .go and input*.txt files, rebuilds,
+// runs against each input, and prints the answers next to those from the
+// previous run (and the expected answers from expected.txt, if there are any).
+//
+//	go run ./cmd/watch -dir ../day17 -part 1
+//	go run ./cmd/watch --year 2022 --day 17
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kentquirk/aoc2022/lib/aoc"
+)
+
+// returns a fingerprint of the files we care about; it changes when any of
+// them are added, removed or modified
+func snapshot(dir string) string {
+	var names []string
+	for _, pat := range []string{"*.go", "input*.txt", "go.mod", aoc.ExpectedFile} {
+		m, _ := filepath.Glob(filepath.Join(dir, pat))
+		names = append(names, m...)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, n := range names {
+		if fi, err := os.Stat(n); err == nil {
+			fmt.Fprintf(&b, "%s %d %d\n", n, fi.Size(), fi.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
+
+func main() {
+	dir := flag.String("dir", ".", "day directory")
+	year := flag.Int("year", aoc.LegacyYear, "puzzle year, used with -day")
+	day := flag.Int("day", 0, "puzzle day; selects the directory instead of -dir")
+	part := flag.Int("part", 0, "part to show (1 or 2); 0 shows both")
+	inputs := flag.String("inputs", "inputsample.txt,input.txt", "comma-separated input files to run, relative to -dir")
+	interval := flag.Duration("interval", 500*time.Millisecond, "how often to check for changes")
+	timeout := flag.Duration("timeout", time.Minute, "time limit for each run")
+	once := flag.Bool("once", false, "run once and exit instead of watching")
+	flag.Parse()
+
+	d, err := aoc.ResolveDir(*dir, *year, *day)
+	if err != nil {
+		log.Fatal(err)
+	}
+	*dir = d
+
+	prev := make(map[string]aoc.Result)
+	last := ""
+	for {
+		snap := snapshot(*dir)
+		if snap != last {
+			last = snap
+			run(*dir, strings.Split(*inputs, ","), *part, *timeout, prev)
+			if *once {
+				return
+			}
+			fmt.Println("watching for changes...")
+		}
+		time.Sleep(*interval)
+	}
+}
+
+func run(dir string, inputs []string, part int, timeout time.Duration, prev map[string]aoc.Result) {
+	fmt.Printf("\n==== %s %s\n", dir, time.Now().Format("15:04:05"))
+	solver, err := aoc.Build(dir)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer solver.Close()
+
+	expected, err := aoc.ReadExpected(dir)
+	if err != nil {
+		log.Println(err)
+	}
+	parts := []int{1, 2}
+	if part != 0 {
+		parts = []int{part}
+	}
+
+	for _, in := range inputs {
+		in = strings.TrimSpace(in)
+		path := filepath.Join(dir, in)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		res := solver.Run(path, 2, timeout)
+		old, hadOld := prev[in]
+		prev[in] = res
+
+		fmt.Printf("%-20s %10v", in, res.Duration.Round(time.Millisecond))
+		if hadOld {
+			fmt.Printf("  (was %v)", old.Duration.Round(time.Millisecond))
+		}
+		fmt.Println()
+		if res.Failed() {
+			fmt.Printf("    ERROR: %v\n", res.Err)
+			continue
+		}
+		for _, p := range parts {
+			ans := res.Answer(p)
+			fmt.Printf("    part %d: %-20s", p, ans)
+			if hadOld && !old.Failed() {
+				if was := old.Answer(p); was != ans {
+					fmt.Printf(" was %s", was)
+				} else {
+					fmt.Print(" unchanged")
+				}
+			}
+			if want, ok := expected.Get(in, p); ok {
+				if want == ans {
+					fmt.Print("  ok")
+				} else {
+					fmt.Printf("  WRONG, want %s", want)
+				}
+			}
+			fmt.Println()
+		}
+	}
+}
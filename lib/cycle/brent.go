@@ -0,0 +1,60 @@
+package cycle
+
+// Brent finds a cycle using Brent's algorithm, which only keeps two states in
+// memory at a time instead of a map of every key seen. The trade-off is that it
+// runs the simulation from the start more than once, so step must not modify
+// its argument: it has to return a new state, leaving the old one intact.
+//
+// After the cycle is located it is verified by a final run over two extra
+// periods, so a hash collision produces a false result rather than a wrong
+// answer. Limit bounds the number of steps taken in each phase.
+func Brent[S any, K comparable](start S, step func(S) S, key func(S) K, metric func(S) int, limit int) (Cycle, bool) {
+	// find the period: the hare moves ahead, and the tortoise teleports to
+	// the hare every time the distance between them reaches a power of two
+	power, period := 1, 1
+	tortoise := key(start)
+	hare := step(start)
+	for key(hare) != tortoise {
+		if power == period {
+			tortoise = key(hare)
+			power *= 2
+			period = 0
+		}
+		hare = step(hare)
+		period++
+		if power > limit {
+			return Cycle{}, false
+		}
+	}
+
+	// find the start: walk two states a period apart until they meet
+	t, h := start, start
+	for i := 0; i < period; i++ {
+		h = step(h)
+	}
+	mu := 0
+	for key(t) != key(h) {
+		t = step(t)
+		h = step(h)
+		mu++
+		if mu > limit {
+			return Cycle{}, false
+		}
+	}
+
+	// verify by replaying the simulation and comparing each period with the last
+	d := NewDetector[K](0)
+	d.start = mu
+	d.period = period
+	s := start
+	for i := 0; i <= mu+3*period; i++ {
+		d.keys = append(d.keys, key(s))
+		d.metrics = append(d.metrics, metric(s))
+		if i > mu+period && !d.matches(i) {
+			return Cycle{}, false
+		}
+		s = step(s)
+	}
+	d.found = true
+	return d.Cycle()
+}
@@ -0,0 +1,132 @@
+// Package cycle finds repeating sections in step-by-step simulations so that
+// a per-step metric (tower height, elves moved, items inspected) can be
+// extrapolated to a step count far too large to simulate directly.
+//
+// A simulation is described by a key for each state and an integer metric. The
+// key is usually a hash of the parts of the state that determine the future
+// (for day 17, the top of the tower plus the wind and rock indices). Because a
+// hash can collide, a candidate cycle is only accepted after the keys repeat for
+// a further stretch of steps and the metric grows by the same amount each period.
+package cycle
+
+// Cycle describes a simulation whose state after Start steps repeats every
+// Period steps. Metrics holds the metric for steps 0 through Start+Period.
+type Cycle struct {
+	Start   int
+	Period  int
+	Metrics []int
+}
+
+// Delta is how much the metric grows across one period.
+func (c Cycle) Delta() int {
+	return c.Metrics[c.Start+c.Period] - c.Metrics[c.Start]
+}
+
+// At returns the metric after n steps.
+func (c Cycle) At(n int) int {
+	if n < len(c.Metrics) {
+		return c.Metrics[n]
+	}
+	periods := (n - c.Start) / c.Period
+	offset := (n - c.Start) % c.Period
+	return c.Metrics[c.Start+offset] + periods*c.Delta()
+}
+
+// Detector finds a cycle in a simulation that is being run by the caller. Call
+// Observe once for every step, starting with the initial state as step 0.
+type Detector[K comparable] struct {
+	// Confirm is the number of steps that must match a candidate cycle before
+	// it is accepted; zero means two full periods.
+	Confirm int
+
+	seen    map[K]int
+	keys    []K
+	metrics []int
+
+	// the candidate cycle being verified, if any
+	start  int
+	period int
+	found  bool
+}
+
+func NewDetector[K comparable](confirm int) *Detector[K] {
+	return &Detector[K]{
+		Confirm: confirm,
+		seen:    make(map[K]int),
+	}
+}
+
+// Step returns the number of steps observed so far.
+func (d *Detector[K]) Step() int {
+	return len(d.keys)
+}
+
+// Observe records the key and metric for the next step. It returns true once a
+// cycle has been found and verified, after which Cycle may be called.
+func (d *Detector[K]) Observe(key K, metric int) bool {
+	if d.found {
+		return true
+	}
+	n := len(d.keys)
+	d.keys = append(d.keys, key)
+	d.metrics = append(d.metrics, metric)
+
+	if d.period > 0 && !d.matches(n) {
+		// the candidate was a collision; keep looking
+		d.period = 0
+	}
+	if d.period > 0 {
+		if n-(d.start+d.period) >= d.confirm() {
+			d.found = true
+			return true
+		}
+	} else if prev, ok := d.seen[key]; ok {
+		d.start = prev
+		d.period = n - prev
+	}
+	d.seen[key] = n
+	return false
+}
+
+func (d *Detector[K]) confirm() int {
+	if d.Confirm > 0 {
+		return d.Confirm
+	}
+	return 2 * d.period
+}
+
+// checks that step n agrees with the step one period earlier
+func (d *Detector[K]) matches(n int) bool {
+	base := d.start + d.period
+	delta := d.metrics[base] - d.metrics[d.start]
+	return d.keys[n] == d.keys[n-d.period] &&
+		d.metrics[n]-d.metrics[n-d.period] == delta
+}
+
+// Cycle returns the verified cycle; ok is false if none has been found yet.
+func (d *Detector[K]) Cycle() (Cycle, bool) {
+	if !d.found {
+		return Cycle{}, false
+	}
+	end := d.start + d.period + 1
+	return Cycle{
+		Start:   d.start,
+		Period:  d.period,
+		Metrics: append([]int(nil), d.metrics[:end]...),
+	}, true
+}
+
+// Find runs a simulation from start, remembering every key in a map, until it
+// finds a verified cycle or has taken limit steps. The step function must
+// return the next state; it may modify and return its argument.
+func Find[S any, K comparable](start S, step func(S) S, key func(S) K, metric func(S) int, limit int) (Cycle, bool) {
+	d := NewDetector[K](0)
+	s := start
+	for i := 0; i <= limit; i++ {
+		if d.Observe(key(s), metric(s)) {
+			return d.Cycle()
+		}
+		s = step(s)
+	}
+	return Cycle{}, false
+}
@@ -0,0 +1,160 @@
+package cycle
+
+import "testing"
+
+// the state of the map x -> (x*x + 7) mod 101, along with the running total of
+// x, which is the metric
+type state struct {
+	x   int
+	sum int
+}
+
+func step(s state) state {
+	x := (s.x*s.x + 7) % 101
+	return state{x: x, sum: s.sum + x}
+}
+
+func key(s state) int    { return s.x }
+func metric(s state) int { return s.sum }
+
+// brute runs the map for n steps
+func brute(start state, n int) []int {
+	m := make([]int, n+1)
+	s := start
+	for i := 0; i <= n; i++ {
+		m[i] = s.sum
+		s = step(s)
+	}
+	return m
+}
+
+// the true pre-period and period, found by remembering every state
+func truth(start state) (int, int) {
+	seen := map[int]int{}
+	s := start
+	for i := 0; ; i++ {
+		if prev, ok := seen[s.x]; ok {
+			return prev, i - prev
+		}
+		seen[s.x] = i
+		s = step(s)
+	}
+}
+
+type finder func(state, func(state) state, func(state) int, func(state) int, int) (Cycle, bool)
+
+var finders = map[string]finder{
+	"Find":  Find[state, int],
+	"Brent": Brent[state, int],
+}
+
+func checkAt(t *testing.T, c Cycle, want []int) {
+	t.Helper()
+	for n, w := range want {
+		if got := c.At(n); got != w {
+			t.Fatalf("At(%d) = %d, want %d", n, got, w)
+		}
+	}
+}
+
+func TestCycle(t *testing.T) {
+	for name, find := range finders {
+		for x := 0; x < 101; x += 10 {
+			start := state{x: x, sum: x}
+			c, ok := find(start, step, key, metric, 10000)
+			if !ok {
+				t.Fatalf("%s from %d: no cycle", name, x)
+			}
+			mu, period := truth(start)
+			if c.Start != mu || c.Period != period {
+				t.Errorf("%s from %d: cycle %d+%d, want %d+%d", name, x, c.Start, c.Period, mu, period)
+			}
+			checkAt(t, c, brute(start, 5000))
+		}
+	}
+}
+
+func TestCycleAtFarAway(t *testing.T) {
+	start := state{x: 3, sum: 3}
+	c, _ := Find(start, step, key, metric, 10000)
+	const n = 1_000_000
+	if got, want := c.At(n), brute(start, n)[n]; got != want {
+		t.Errorf("At(%d) = %d, want %d", n, got, want)
+	}
+}
+
+func TestCollidingKey(t *testing.T) {
+	start := state{x: 3, sum: 3}
+	mu, _ := truth(start)
+	if mu < 2 {
+		t.Fatalf("need a pre-period of at least 2, got %d", mu)
+	}
+	// make the first state collide with one in the cycle that isn't the same
+	// state, so the keys repeat before the states do
+	states := []state{start}
+	for i := 0; i < mu+1; i++ {
+		states = append(states, step(states[len(states)-1]))
+	}
+	fake := states[0].x
+	collider := states[mu].x
+	bad := func(s state) int {
+		if s.x == collider {
+			return fake
+		}
+		return s.x
+	}
+	want := brute(start, 5000)
+	for name, find := range finders {
+		c, ok := find(start, step, bad, metric, 10000)
+		if !ok {
+			// Find keeps looking after a collision, so it should get there
+			if name == "Find" {
+				t.Errorf("Find gave up after the collision")
+			}
+			continue
+		}
+		if c.Start == 0 {
+			t.Errorf("%s accepted the colliding cycle %d+%d", name, c.Start, c.Period)
+		}
+		checkAt(t, c, want)
+	}
+}
+
+func TestDetectorConfirm(t *testing.T) {
+	// keys repeat with period 3 for a while, then change: a short confirmation
+	// is fooled, the default one isn't
+	keys := []int{1, 2, 3, 1, 2, 3, 1, 4, 5, 6, 4, 5, 6, 4, 5, 6, 4, 5, 6, 4}
+	short := NewDetector[int](1)
+	for i, k := range keys {
+		if short.Observe(k, i) {
+			break
+		}
+	}
+	if c, ok := short.Cycle(); !ok || c.Start != 0 {
+		t.Errorf("short confirmation found %+v %v, expected the early false cycle", c, ok)
+	}
+
+	d := NewDetector[int](0)
+	found := false
+	for i, k := range keys {
+		if d.Observe(k, i) {
+			found = true
+			break
+		}
+	}
+	c, ok := d.Cycle()
+	if !found || !ok || c.Start != 7 || c.Period != 3 {
+		t.Errorf("default confirmation found %+v %v, want start 7 period 3", c, ok)
+	}
+	if d.Step() != 17 {
+		t.Errorf("stopped after %d steps, want 17", d.Step())
+	}
+}
+
+func TestLimit(t *testing.T) {
+	for name, find := range finders {
+		if _, ok := find(state{x: 3, sum: 3}, step, key, metric, 2); ok {
+			t.Errorf("%s found a cycle within a limit of 2 steps", name)
+		}
+	}
+}
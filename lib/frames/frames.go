@@ -0,0 +1,216 @@
+// Package frames records snapshots of a grid simulation (rope knots, falling
+// sand, rocks, elves) and turns them into images: a PNG per frame, or a single
+// animated GIF.
+//
+// Each cell of a snapshot holds a small value that indexes the recorder's
+// palette, so the simulation decides what its states mean and the recorder
+// decides what they look like. Snapshots are positioned in the simulation's own
+// coordinates, and may grow or move from frame to frame; every image is drawn
+// over the union of all the frames so that nothing jumps around.
+package frames
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Frame is one snapshot. Cell (x, y) in simulation coordinates is stored at
+// Cells[(y-MinY)*Width + (x-MinX)].
+type Frame struct {
+	MinX   int
+	MinY   int
+	Width  int
+	Height int
+	Cells  []uint8
+	Delay  int // in hundredths of a second; 0 means use the recorder's Delay
+}
+
+func (f *Frame) At(x, y int) uint8 {
+	x -= f.MinX
+	y -= f.MinY
+	if x < 0 || y < 0 || x >= f.Width || y >= f.Height {
+		return 0
+	}
+	return f.Cells[y*f.Width+x]
+}
+
+// Snapshot builds a frame covering minX..maxX and minY..maxY inclusive, asking
+// value for the contents of each cell.
+func Snapshot(minX, minY, maxX, maxY int, value func(x, y int) uint8) *Frame {
+	f := &Frame{
+		MinX:   minX,
+		MinY:   minY,
+		Width:  maxX - minX + 1,
+		Height: maxY - minY + 1,
+	}
+	f.Cells = make([]uint8, f.Width*f.Height)
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			f.Cells[(y-minY)*f.Width+(x-minX)] = value(x, y)
+		}
+	}
+	return f
+}
+
+// DefaultPalette has a dark background, then white, red, green, blue, yellow,
+// cyan, magenta and grey.
+var DefaultPalette = color.Palette{
+	color.RGBA{0x10, 0x10, 0x18, 0xff},
+	color.RGBA{0xee, 0xee, 0xee, 0xff},
+	color.RGBA{0xdd, 0x33, 0x33, 0xff},
+	color.RGBA{0x33, 0xbb, 0x44, 0xff},
+	color.RGBA{0x33, 0x66, 0xdd, 0xff},
+	color.RGBA{0xee, 0xcc, 0x22, 0xff},
+	color.RGBA{0x22, 0xcc, 0xcc, 0xff},
+	color.RGBA{0xcc, 0x44, 0xcc, 0xff},
+	color.RGBA{0x77, 0x77, 0x77, 0xff},
+}
+
+// Recorder collects frames.
+type Recorder struct {
+	Palette color.Palette
+	Scale   int // pixels per cell
+	Delay   int // default delay between GIF frames, in hundredths of a second
+	Frames  []*Frame
+}
+
+func NewRecorder(scale int) *Recorder {
+	return &Recorder{
+		Palette: DefaultPalette,
+		Scale:   scale,
+		Delay:   10,
+	}
+}
+
+func (r *Recorder) Add(f *Frame) {
+	r.Frames = append(r.Frames, f)
+}
+
+// Record is shorthand for Add(Snapshot(...)).
+func (r *Recorder) Record(minX, minY, maxX, maxY int, value func(x, y int) uint8) {
+	r.Add(Snapshot(minX, minY, maxX, maxY, value))
+}
+
+// Bounds returns the union of all the frames, as inclusive min and max corners.
+func (r *Recorder) Bounds() (image.Point, image.Point) {
+	var min, max image.Point
+	for i, f := range r.Frames {
+		fmin := image.Pt(f.MinX, f.MinY)
+		fmax := image.Pt(f.MinX+f.Width-1, f.MinY+f.Height-1)
+		if i == 0 {
+			min, max = fmin, fmax
+			continue
+		}
+		if fmin.X < min.X {
+			min.X = fmin.X
+		}
+		if fmin.Y < min.Y {
+			min.Y = fmin.Y
+		}
+		if fmax.X > max.X {
+			max.X = fmax.X
+		}
+		if fmax.Y > max.Y {
+			max.Y = fmax.Y
+		}
+	}
+	return min, max
+}
+
+// Image renders frame n over the recorder's bounds.
+func (r *Recorder) Image(n int) *image.Paletted {
+	min, max := r.Bounds()
+	return r.image(n, min, max)
+}
+
+// image renders frame n over the given bounds, which the callers that render
+// every frame work out just once.
+func (r *Recorder) image(n int, min, max image.Point) *image.Paletted {
+	scale := r.Scale
+	if scale < 1 {
+		scale = 1
+	}
+	w := (max.X - min.X + 1) * scale
+	h := (max.Y - min.Y + 1) * scale
+	img := image.NewPaletted(image.Rect(0, 0, w, h), r.Palette)
+	f := r.Frames[n]
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			v := f.At(x, y)
+			if int(v) >= len(r.Palette) {
+				v = uint8(len(r.Palette) - 1)
+			}
+			if v == 0 {
+				continue // already background
+			}
+			px := (x - min.X) * scale
+			py := (y - min.Y) * scale
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(px+dx, py+dy, v)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WriteGIF writes all the frames as an animated GIF that loops forever.
+func (r *Recorder) WriteGIF(w io.Writer) error {
+	if len(r.Frames) == 0 {
+		return fmt.Errorf("frames: nothing recorded")
+	}
+	if len(r.Palette) > 256 {
+		return fmt.Errorf("frames: GIF palette can have at most 256 colors, not %d", len(r.Palette))
+	}
+	min, max := r.Bounds()
+	anim := &gif.GIF{}
+	for i, f := range r.Frames {
+		delay := f.Delay
+		if delay == 0 {
+			delay = r.Delay
+		}
+		anim.Image = append(anim.Image, r.image(i, min, max))
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// SaveGIF writes the animation to the named file.
+func (r *Recorder) SaveGIF(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := r.WriteGIF(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// SavePNGs writes each frame to dir as prefix0000.png, prefix0001.png and so on.
+func (r *Recorder) SavePNGs(dir string, prefix string) error {
+	min, max := r.Bounds()
+	for i := range r.Frames {
+		name := filepath.Join(dir, fmt.Sprintf("%s%04d.png", prefix, i))
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		if err := png.Encode(f, r.image(i, min, max)); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
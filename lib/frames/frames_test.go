@@ -0,0 +1,105 @@
+package frames
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// two frames: a 2x2 block at the origin, then a 3x1 strip up and to the left
+func twoFrames() *Recorder {
+	r := NewRecorder(3)
+	r.Record(0, 0, 1, 1, func(x, y int) uint8 { return uint8(1 + x + 2*y) })
+	r.Record(-2, -1, 0, -1, func(x, y int) uint8 { return 200 })
+	r.Frames[1].Delay = 50
+	return r
+}
+
+func TestBounds(t *testing.T) {
+	min, max := twoFrames().Bounds()
+	if min != image.Pt(-2, -1) || max != image.Pt(1, 1) {
+		t.Errorf("bounds %v..%v, want (-2,-1)..(1,1)", min, max)
+	}
+}
+
+func TestImage(t *testing.T) {
+	r := twoFrames()
+	img := r.Image(0)
+	// 4x3 cells at 3 pixels each
+	if img.Bounds() != image.Rect(0, 0, 12, 9) {
+		t.Fatalf("image bounds %v", img.Bounds())
+	}
+	// cell (1, 1) has value 4 and sits at cell offset (3, 2) from the corner
+	for dy := 0; dy < 3; dy++ {
+		for dx := 0; dx < 3; dx++ {
+			if v := img.ColorIndexAt(9+dx, 6+dy); v != 4 {
+				t.Errorf("pixel %d,%d is %d, want 4", 9+dx, 6+dy, v)
+			}
+		}
+	}
+	// cell (1, 0) has value 2
+	if v := img.ColorIndexAt(11, 5); v != 2 || img.ColorIndexAt(0, 0) != 0 {
+		t.Errorf("wrong pixels outside the block")
+	}
+
+	// values past the end of the palette use its last color
+	last := uint8(len(r.Palette) - 1)
+	if v := r.Image(1).ColorIndexAt(0, 0); v != last {
+		t.Errorf("clamped pixel is %d, want %d", v, last)
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	r := twoFrames()
+	var buf bytes.Buffer
+	if err := r.WriteGIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 {
+		t.Fatalf("%d frames, want 2", len(anim.Image))
+	}
+	if anim.Delay[0] != 10 || anim.Delay[1] != 50 {
+		t.Errorf("delays %v, want [10 50]", anim.Delay)
+	}
+	for i, img := range anim.Image {
+		if img.Bounds() != image.Rect(0, 0, 12, 9) {
+			t.Errorf("frame %d bounds %v", i, img.Bounds())
+		}
+	}
+	if anim.Image[0].ColorIndexAt(10, 7) != 4 {
+		t.Errorf("decoded pixel is %d, want 4", anim.Image[0].ColorIndexAt(10, 7))
+	}
+
+	if err := NewRecorder(1).WriteGIF(&buf); err == nil {
+		t.Error("empty recorder wrote a GIF")
+	}
+}
+
+func TestSavePNGs(t *testing.T) {
+	dir := t.TempDir()
+	if err := twoFrames().SavePNGs(dir, "f"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"f0000.png", "f0001.png"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds() != image.Rect(0, 0, 12, 9) {
+			t.Errorf("%s bounds %v", name, img.Bounds())
+		}
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/kentquirk/aoc2022/lib
+
+go 1.19
+
+require github.com/dgryski/go-wyhash v0.0.0-20191203203029-c4841ae36371
@@ -0,0 +1,238 @@
+// Package intmath has the integer number theory that keeps coming up in the
+// puzzles: gcd and lcm for combining monkey moduli, mod and div that behave
+// sensibly with negative numbers (rotating day 20's ring backwards), modular
+// arithmetic that doesn't overflow, and the Chinese Remainder Theorem.
+package intmath
+
+import (
+	"errors"
+	"math/bits"
+)
+
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type Integer interface {
+	Signed | Unsigned
+}
+
+func Abs[T Integer](a T) T {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// GCD returns the greatest common divisor of a and b, which is never negative.
+// GCD(0, 0) is 0.
+func GCD[T Integer](a, b T) T {
+	a, b = Abs(a), Abs(b)
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// LCM returns the least common multiple of a and b, which is never negative.
+// LCM of anything with 0 is 0. It does not check for overflow; see LCMChecked.
+func LCM[T Integer](a, b T) T {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return Abs(a / GCD(a, b) * b)
+}
+
+// LCMChecked is LCM, but returns false if the result doesn't fit in T.
+func LCMChecked[T Integer](a, b T) (T, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	l, ok := MulChecked(Abs(a/GCD(a, b)), Abs(b))
+	if !ok || l < 0 {
+		return 0, false
+	}
+	return l, true
+}
+
+// GCDAll returns the gcd of all its arguments (0 if there are none).
+func GCDAll[T Integer](vals ...T) T {
+	var g T
+	for _, v := range vals {
+		g = GCD(g, v)
+	}
+	return g
+}
+
+// LCMAll returns the lcm of all its arguments (1 if there are none).
+func LCMAll[T Integer](vals ...T) T {
+	var l T = 1
+	for _, v := range vals {
+		l = LCM(l, v)
+	}
+	return l
+}
+
+// Mod is the floored modulus: the result has the same sign as m, so
+// Mod(-1, 5) is 4 and Mod(1, -5) is -4. Go's % operator truncates instead,
+// giving -1 and 1. Panics if m is 0.
+func Mod[T Integer](a, m T) T {
+	r := a % m
+	if r != 0 && (r < 0) != (m < 0) {
+		r += m
+	}
+	return r
+}
+
+// Div is the floored quotient that goes with Mod, so that
+// Div(a, m)*m + Mod(a, m) == a. Div(-1, 5) is -1, where Go's / gives 0.
+func Div[T Integer](a, m T) T {
+	q := a / m
+	if r := a % m; r != 0 && (r < 0) != (m < 0) {
+		q--
+	}
+	return q
+}
+
+// EuclidMod is the Euclidean modulus, which is never negative whatever the
+// signs of a and m: EuclidMod(-1, 5) and EuclidMod(-1, -5) are both 4.
+func EuclidMod[T Integer](a, m T) T {
+	r := a % m
+	if r < 0 {
+		r += Abs(m)
+	}
+	return r
+}
+
+// EuclidDiv is the quotient that goes with EuclidMod, so that
+// EuclidDiv(a, m)*m + EuclidMod(a, m) == a.
+func EuclidDiv[T Integer](a, m T) T {
+	q := a / m
+	if a%m < 0 {
+		if m > 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+// MulChecked returns a*b and true, or 0 and false if the product overflows T.
+func MulChecked[T Integer](a, b T) (T, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	// the sign test catches MinInt * -1, which the division tests can't
+	if c/b != a || c/a != b || ((a < 0) != (b < 0)) != (c < 0) {
+		return 0, false
+	}
+	return c, true
+}
+
+// AddChecked returns a+b and true, or 0 and false if the sum overflows T.
+func AddChecked[T Integer](a, b T) (T, bool) {
+	c := a + b
+	if (b > 0 && c < a) || (b < 0 && c > a) {
+		return 0, false
+	}
+	return c, true
+}
+
+// MulMod returns a*b mod m (in the range [0, m)) without overflowing, even when
+// a*b wouldn't fit in T. m must be positive.
+func MulMod[T Integer](a, b, m T) T {
+	mm := uint64(m)
+	hi, lo := bits.Mul64(uint64(EuclidMod(a, m)), uint64(EuclidMod(b, m)))
+	return T(bits.Rem64(hi, lo, mm))
+}
+
+// PowMod returns base**exp mod m (in the range [0, m)) by repeated squaring.
+// exp must not be negative and m must be positive.
+func PowMod[T Integer](base, exp, m T) T {
+	if exp < 0 {
+		panic("intmath: negative exponent in PowMod")
+	}
+	result := EuclidMod(1, m)
+	base = EuclidMod(base, m)
+	for exp > 0 {
+		if exp&1 == 1 {
+			result = MulMod(result, base, m)
+		}
+		base = MulMod(base, base, m)
+		exp >>= 1
+	}
+	return result
+}
+
+// ExtGCD returns g = GCD(a, b) along with x and y such that a*x + b*y == g.
+func ExtGCD[T Signed](a, b T) (g, x, y T) {
+	oldR, r := a, b
+	oldS, s := T(1), T(0)
+	oldT, t := T(0), T(1)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldS, s = s, oldS-q*s
+		oldT, t = t, oldT-q*t
+	}
+	if oldR < 0 {
+		return -oldR, -oldS, -oldT
+	}
+	return oldR, oldS, oldT
+}
+
+// ModInverse returns x in [0, m) such that a*x mod m is 1, or false if a and m
+// aren't coprime. m must be positive.
+func ModInverse[T Signed](a, m T) (T, bool) {
+	g, x, _ := ExtGCD(EuclidMod(a, m), m)
+	if g != 1 {
+		return 0, false
+	}
+	return EuclidMod(x, m), true
+}
+
+var (
+	ErrNoSolution = errors.New("intmath: congruences have no common solution")
+	ErrOverflow   = errors.New("intmath: combined modulus overflows")
+	ErrBadModulus = errors.New("intmath: moduli must be positive")
+)
+
+// CRT solves the system x ≡ residues[i] (mod moduli[i]) and returns the
+// smallest non-negative solution x along with the modulus of the solution (the
+// lcm of the moduli); every solution is x plus a multiple of it. The moduli
+// need not be coprime, as long as the congruences agree where they overlap.
+// Residues may be negative or larger than their modulus.
+func CRT[T Signed](residues, moduli []T) (x T, m T, err error) {
+	if len(residues) != len(moduli) {
+		panic("intmath: CRT needs one modulus per residue")
+	}
+	x, m = 0, 1
+	for i := range residues {
+		mi := moduli[i]
+		if mi <= 0 {
+			return 0, 0, ErrBadModulus
+		}
+		ri := EuclidMod(residues[i], mi)
+		g, p, _ := ExtGCD(m, mi)
+		diff := EuclidMod(ri-x, mi)
+		if diff%g != 0 {
+			return 0, 0, ErrNoSolution
+		}
+		step := mi / g
+		newM, ok := MulChecked(m, step)
+		if !ok {
+			return 0, 0, ErrOverflow
+		}
+		// x + m*k satisfies both congruences, where k = (diff/g) * p mod step
+		k := MulMod(diff/g, p, step)
+		x = EuclidMod(x+MulMod(m, k, newM), newM)
+		m = newM
+	}
+	return x, m, nil
+}
@@ -0,0 +1,147 @@
+package intmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestModDiv(t *testing.T) {
+	tests := []struct {
+		a, m               int
+		mod, div           int
+		euclidMod, euclidD int
+	}{
+		{7, 3, 1, 2, 1, 2},
+		{-7, 3, 2, -3, 2, -3},
+		{7, -3, -2, -3, 1, -2},
+		{-7, -3, -1, 2, 2, 3},
+		{6, 3, 0, 2, 0, 2},
+		{-6, 3, 0, -2, 0, -2},
+		{6, -3, 0, -2, 0, -2},
+		{-6, -3, 0, 2, 0, 2},
+		{0, 5, 0, 0, 0, 0},
+		{0, -5, 0, 0, 0, 0},
+		{-1, 5, 4, -1, 4, -1},
+		{1, -5, -4, -1, 1, 0},
+	}
+	for _, tt := range tests {
+		if got := Mod(tt.a, tt.m); got != tt.mod {
+			t.Errorf("Mod(%d, %d) = %d, want %d", tt.a, tt.m, got, tt.mod)
+		}
+		if got := Div(tt.a, tt.m); got != tt.div {
+			t.Errorf("Div(%d, %d) = %d, want %d", tt.a, tt.m, got, tt.div)
+		}
+		if got := EuclidMod(tt.a, tt.m); got != tt.euclidMod {
+			t.Errorf("EuclidMod(%d, %d) = %d, want %d", tt.a, tt.m, got, tt.euclidMod)
+		}
+		if got := EuclidDiv(tt.a, tt.m); got != tt.euclidD {
+			t.Errorf("EuclidDiv(%d, %d) = %d, want %d", tt.a, tt.m, got, tt.euclidD)
+		}
+	}
+}
+
+func TestModDivIdentity(t *testing.T) {
+	for a := -20; a <= 20; a++ {
+		for m := -7; m <= 7; m++ {
+			if m == 0 {
+				continue
+			}
+			if got := Div(a, m)*m + Mod(a, m); got != a {
+				t.Errorf("Div(%d, %d)*m + Mod = %d", a, m, got)
+			}
+			if r := Mod(a, m); r != 0 && (r < 0) != (m < 0) {
+				t.Errorf("Mod(%d, %d) = %d has the wrong sign", a, m, r)
+			}
+			if got := EuclidDiv(a, m)*m + EuclidMod(a, m); got != a {
+				t.Errorf("EuclidDiv(%d, %d)*m + EuclidMod = %d", a, m, got)
+			}
+			if r := EuclidMod(a, m); r < 0 || r >= Abs(m) {
+				t.Errorf("EuclidMod(%d, %d) = %d is out of range", a, m, r)
+			}
+		}
+	}
+}
+
+func TestMulChecked(t *testing.T) {
+	tests := []struct {
+		a, b int64
+		want int64
+		ok   bool
+	}{
+		{math.MinInt64, -1, 0, false},
+		{-1, math.MinInt64, 0, false},
+		{math.MinInt64, 1, math.MinInt64, true},
+		{math.MaxInt64, -1, -math.MaxInt64, true},
+		{math.MaxInt64, 2, 0, false},
+		{math.MinInt64 / 2, 2, math.MinInt64, true},
+		{math.MinInt64 / 2, -2, 0, false},
+		{-3, -4, 12, true},
+		{0, math.MinInt64, 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := MulChecked(tt.a, tt.b)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("MulChecked(%d, %d) = %d %v, want %d %v", tt.a, tt.b, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		name     string
+		residues []int64
+		moduli   []int64
+		x, m     int64
+		err      error
+	}{
+		{"coprime", []int64{2, 3, 2}, []int64{3, 5, 7}, 23, 105, nil},
+		{"not coprime", []int64{3, 7}, []int64{4, 6}, 7, 12, nil},
+		{"not coprime, shared factor", []int64{5, 11, 3}, []int64{6, 9, 4}, 11, 36, nil},
+		{"conflicting", []int64{1, 2}, []int64{4, 6}, 0, 0, ErrNoSolution},
+		{"conflicting, same modulus", []int64{1, 2}, []int64{5, 5}, 0, 0, ErrNoSolution},
+		{"negative residues", []int64{-1, -2}, []int64{5, 7}, 19, 35, nil},
+		{"large residues", []int64{26, 72}, []int64{5, 7}, 16, 35, nil},
+		{"bad modulus", []int64{1}, []int64{-5}, 0, 0, ErrBadModulus},
+		{"overflow", []int64{1, 1}, []int64{math.MaxInt64, math.MaxInt64 - 1}, 0, 0, ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, m, err := CRT(tt.residues, tt.moduli)
+			if x != tt.x || m != tt.m || err != tt.err {
+				t.Fatalf("got %d mod %d (%v), want %d mod %d (%v)", x, m, err, tt.x, tt.m, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			for i := range tt.residues {
+				if EuclidMod(x, tt.moduli[i]) != EuclidMod(tt.residues[i], tt.moduli[i]) {
+					t.Errorf("%d isn't %d mod %d", x, tt.residues[i], tt.moduli[i])
+				}
+			}
+		})
+	}
+}
+
+func TestModInverse(t *testing.T) {
+	tests := []struct {
+		a, m int
+		want int
+		ok   bool
+	}{
+		{3, 7, 5, true},
+		{-3, 7, 2, true},
+		{-1, 5, 4, true},
+		{-10, 7, 2, true},
+		{4, 8, 0, false},
+		{-4, 6, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ModInverse(tt.a, tt.m)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ModInverse(%d, %d) = %d %v, want %d %v", tt.a, tt.m, got, ok, tt.want, tt.ok)
+		}
+		if ok && Mod(tt.a*got, tt.m) != 1 {
+			t.Errorf("ModInverse(%d, %d): %d isn't an inverse", tt.a, tt.m, got)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+// Package linepat pulls typed values out of puzzle input lines without a
+// hand-written regexp and a row of strconv.Atoi calls on submatch indices.
+//
+// A template is literal text with placeholders in braces:
+//
+//	p := linepat.MustCompile("move {int} from {int} to {int}")
+//	var qty, from, to int
+//	err := p.Scan("move 3 from 1 to 2", &qty, &from, &to)
+//
+// Placeholders can be named so that Unmarshal can fill a struct:
+//
+//	type Command struct {
+//		Qty  int
+//		FrIx int `linepat:"from"`
+//		ToIx int `linepat:"to"`
+//	}
+//	p := linepat.MustCompile("move {int:qty} from {int:from} to {int:to}")
+//	cmd, err := linepat.Parse[Command](p, line)
+//
+// The template must match the whole line, apart from leading and trailing
+// white space; a run of spaces in the template matches any run of white space.
+// Use {{ and }} for literal braces.
+package linepat
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The placeholder kinds and what they match.
+var kinds = map[string]string{
+	"int":   `[-+]?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"float": `[-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?`,
+	"word":  `\w+`,
+	"str":   `.*?`,
+	"ints":  `.*?`, // every signed integer in the text, into a []int
+}
+
+var ErrNoMatch = errors.New("linepat: line does not match pattern")
+
+type field struct {
+	kind string
+	name string
+}
+
+// Pattern is a compiled template.
+type Pattern struct {
+	template string
+	re       *regexp.Regexp
+	fields   []field
+}
+
+var spaces = regexp.MustCompile(` +`)
+
+func Compile(template string) (*Pattern, error) {
+	p := &Pattern{template: template}
+	var re strings.Builder
+	re.WriteString(`^\s*`)
+	literal := func(s string) {
+		for i, part := range spaces.Split(s, -1) {
+			if i > 0 {
+				re.WriteString(`\s+`)
+			}
+			re.WriteString(regexp.QuoteMeta(part))
+		}
+	}
+	rest := template
+	var text strings.Builder
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, "{{"):
+			text.WriteByte('{')
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "}}"):
+			text.WriteByte('}')
+			rest = rest[2:]
+		case rest[0] == '{':
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return nil, fmt.Errorf("linepat: unclosed placeholder in %q", template)
+			}
+			kind, name, _ := strings.Cut(rest[1:end], ":")
+			expr, ok := kinds[kind]
+			if !ok {
+				return nil, fmt.Errorf("linepat: unknown placeholder {%s} in %q", kind, template)
+			}
+			literal(text.String())
+			text.Reset()
+			re.WriteString("(" + expr + ")")
+			p.fields = append(p.fields, field{kind: kind, name: name})
+			rest = rest[end+1:]
+		case rest[0] == '}':
+			return nil, fmt.Errorf("linepat: unmatched } in %q", template)
+		default:
+			text.WriteByte(rest[0])
+			rest = rest[1:]
+		}
+	}
+	literal(text.String())
+	re.WriteString(`\s*$`)
+	var err error
+	p.re, err = regexp.Compile(re.String())
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func MustCompile(template string) *Pattern {
+	p, err := Compile(template)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p *Pattern) String() string {
+	return p.template
+}
+
+// NumFields returns the number of placeholders in the template.
+func (p *Pattern) NumFields() int {
+	return len(p.fields)
+}
+
+// Matches reports whether line fits the template.
+func (p *Pattern) Matches(line string) bool {
+	return p.re.MatchString(line)
+}
+
+func (p *Pattern) match(line string) ([]string, error) {
+	m := p.re.FindStringSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q does not match %q", ErrNoMatch, line, p.template)
+	}
+	return m[1:], nil
+}
+
+// Scan matches line and stores the placeholder values, in order, into dests,
+// which must be pointers to types that fit the placeholders: any integer type
+// for {int} and {uint}, a float type for {float}, a string for {word} and
+// {str}, and a []int for {ints}.
+func (p *Pattern) Scan(line string, dests ...any) error {
+	if len(dests) != len(p.fields) {
+		return fmt.Errorf("linepat: %q has %d fields but Scan got %d destinations", p.template, len(p.fields), len(dests))
+	}
+	values, err := p.match(line)
+	if err != nil {
+		return err
+	}
+	for i, d := range dests {
+		v := reflect.ValueOf(d)
+		if v.Kind() != reflect.Pointer || v.IsNil() {
+			return fmt.Errorf("linepat: destination %d is %T, not a pointer", i, d)
+		}
+		if err := set(v.Elem(), p.fields[i].kind, values[i]); err != nil {
+			return fmt.Errorf("linepat: field %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Unmarshal matches line and stores the placeholder values into the struct
+// that v points to. A named placeholder goes to the field with a matching
+// `linepat:"name"` tag, or failing that the field whose name matches ignoring
+// case. An unnamed placeholder goes to the exported field at the same
+// position as the placeholder.
+func (p *Pattern) Unmarshal(line string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("linepat: Unmarshal needs a pointer to a struct, not %T", v)
+	}
+	values, err := p.match(line)
+	if err != nil {
+		return err
+	}
+	st := rv.Elem()
+	exported := exportedFields(st.Type())
+	for i, f := range p.fields {
+		var target reflect.Value
+		if f.name != "" {
+			ix, ok := fieldByName(st.Type(), f.name)
+			if !ok {
+				return fmt.Errorf("linepat: %s has no field for {%s:%s}", st.Type(), f.kind, f.name)
+			}
+			target = st.Field(ix)
+		} else {
+			if i >= len(exported) {
+				return fmt.Errorf("linepat: %s has no field for placeholder %d", st.Type(), i)
+			}
+			target = st.Field(exported[i])
+		}
+		if err := set(target, f.kind, values[i]); err != nil {
+			return fmt.Errorf("linepat: field %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Parse is Unmarshal for callers who'd rather get the struct back.
+func Parse[T any](p *Pattern, line string) (T, error) {
+	var t T
+	err := p.Unmarshal(line, &t)
+	return t, err
+}
+
+func exportedFields(t reflect.Type) []int {
+	var ixs []int
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() && t.Field(i).Tag.Get("linepat") != "-" {
+			ixs = append(ixs, i)
+		}
+	}
+	return ixs
+}
+
+func fieldByName(t reflect.Type, name string) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() && t.Field(i).Tag.Get("linepat") == name {
+			return i, true
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() && strings.EqualFold(t.Field(i).Name, name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func set(v reflect.Value, kind string, text string) error {
+	switch kind {
+	case "ints":
+		if v.Type() != reflect.TypeOf([]int(nil)) {
+			return fmt.Errorf("can't store {%s} in %s", kind, v.Type())
+		}
+		v.Set(reflect.ValueOf(Ints(text)))
+		return nil
+	case "word", "str":
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("can't store {%s} in %s", kind, v.Type())
+		}
+		v.SetString(text)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if kind == "float" {
+			break
+		}
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if kind == "float" {
+			break
+		}
+		n, err := strconv.ParseUint(strings.TrimPrefix(text, "+"), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+		return nil
+	case reflect.String:
+		v.SetString(text)
+		return nil
+	}
+	return fmt.Errorf("can't store {%s} in %s", kind, v.Type())
+}
+
+var (
+	intpat  = regexp.MustCompile(`-?[0-9]+`)
+	uintpat = regexp.MustCompile(`[0-9]+`)
+)
+
+// Ints returns every integer in s, in order. A minus sign counts as part of the
+// number unless it directly follows a digit, so "x=-2, y=18" gives -2 and 18
+// but a range like "2-4" gives 2 and 4.
+func Ints(s string) []int {
+	var nums []int
+	for _, loc := range intpat.FindAllStringIndex(s, -1) {
+		start := loc[0]
+		if s[start] == '-' && start > 0 && s[start-1] >= '0' && s[start-1] <= '9' {
+			start++
+		}
+		n, _ := strconv.Atoi(s[start:loc[1]])
+		nums = append(nums, n)
+	}
+	return nums
+}
+
+// Uints returns every run of digits in s, ignoring any signs.
+func Uints(s string) []int {
+	var nums []int
+	for _, v := range uintpat.FindAllString(s, -1) {
+		n, _ := strconv.Atoi(v)
+		nums = append(nums, n)
+	}
+	return nums
+}
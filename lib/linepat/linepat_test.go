@@ -0,0 +1,205 @@
+package linepat
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanTemplates(t *testing.T) {
+	tests := []struct {
+		template string
+		line     string
+		want     []int
+	}{
+		{"move {int} from {int} to {int}", "move 3 from 1 to 2", []int{3, 1, 2}},
+		{"move {int} from {int} to {int}", "  move 13   from 1 to 22 ", []int{13, 1, 22}},
+		{"Sensor at x={int}, y={int}: closest beacon is at x={int}, y={int}",
+			"Sensor at x=2, y=18: closest beacon is at x=-2, y=15", []int{2, 18, -2, 15}},
+		{"{int}-{int},{int}-{int}", "2-4,6-8", []int{2, 4, 6, 8}},
+		{"{int}-{int},{int}-{int}", "12-40,6-6", []int{12, 40, 6, 6}},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.template)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", tt.template, err)
+		}
+		got := make([]int, p.NumFields())
+		dests := make([]any, len(got))
+		for i := range got {
+			dests[i] = &got[i]
+		}
+		if err := p.Scan(tt.line, dests...); err != nil {
+			t.Errorf("Scan(%q): %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Scan(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestScanKinds(t *testing.T) {
+	p := MustCompile("{word} {{{uint}}} {float} [{str}] {ints}")
+	var (
+		w  string
+		u  uint8
+		f  float64
+		s  string
+		is []int
+	)
+	if err := p.Scan("monkey {7} -2.5e1 [a b] 1, -2, 3", &w, &u, &f, &s, &is); err != nil {
+		t.Fatal(err)
+	}
+	if w != "monkey" || u != 7 || f != -25 || s != "a b" || !reflect.DeepEqual(is, []int{1, -2, 3}) {
+		t.Errorf("got %q %d %v %q %v", w, u, f, s, is)
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	p := MustCompile("move {int} from {int} to {int}")
+	var a, b, c int
+	for _, line := range []string{"move x from 1 to 2", "move 3 from 1 to 2 now", "turn 1 from 2 to 3", ""} {
+		err := p.Scan(line, &a, &b, &c)
+		if !errors.Is(err, ErrNoMatch) {
+			t.Errorf("Scan(%q) = %v, want ErrNoMatch", line, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), line) {
+			t.Errorf("error %q doesn't mention the line", err)
+		}
+	}
+	if p.Matches("move 1 from 2") {
+		t.Error("partial line matched")
+	}
+	if err := p.Scan("move 1 from 2 to 3", &a, &b); err == nil || errors.Is(err, ErrNoMatch) {
+		t.Errorf("wrong number of destinations gave %v", err)
+	}
+	if err := p.Scan("move 1 from 2 to 3", a, &b, &c); err == nil {
+		t.Error("non-pointer destination accepted")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, template := range []string{
+		"move {number} from",
+		"move {int from {int}",
+		"x={int",
+		"move } from {int}",
+		"x={int}}",
+	} {
+		if _, err := Compile(template); err == nil {
+			t.Errorf("Compile(%q) succeeded", template)
+		}
+	}
+	for _, template := range []string{"{{literal}}", "a {{ {int} }}", "{int:x}"} {
+		if _, err := Compile(template); err != nil {
+			t.Errorf("Compile(%q): %v", template, err)
+		}
+	}
+}
+
+func TestNarrowInts(t *testing.T) {
+	p := MustCompile("{int} {uint}")
+	var small int8
+	var u uint8
+	if err := p.Scan("127 255", &small, &u); err != nil || small != 127 || u != 255 {
+		t.Errorf("in range: %v %d %d", err, small, u)
+	}
+	if err := p.Scan("128 1", &small, &u); err == nil {
+		t.Error("128 fit in an int8")
+	}
+	if err := p.Scan("-129 1", &small, &u); err == nil {
+		t.Error("-129 fit in an int8")
+	}
+	if err := p.Scan("1 256", &small, &u); err == nil {
+		t.Error("256 fit in a uint8")
+	}
+	var neg uint
+	if err := MustCompile("{int}").Scan("-1", &neg); err == nil {
+		t.Error("-1 fit in a uint")
+	}
+	var s string
+	if err := MustCompile("{int}").Scan("5", &s); err != nil || s != "5" {
+		t.Errorf("int into a string: %v %q", err, s)
+	}
+	var f float64
+	if err := MustCompile("{word}").Scan("abc", &f); err == nil {
+		t.Error("word stored in a float")
+	}
+}
+
+type sensor struct {
+	SX, SY int
+	BX     int `linepat:"beaconx"`
+	By     int
+	Note   string `linepat:"-"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	p := MustCompile("Sensor at x={int:sx}, y={int:SY}: closest beacon is at x={int:beaconx}, y={int:BY}")
+	s, err := Parse[sensor](p, "Sensor at x=2, y=18: closest beacon is at x=-2, y=15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != (sensor{SX: 2, SY: 18, BX: -2, By: 15}) {
+		t.Errorf("got %+v", s)
+	}
+
+	type command struct {
+		Qty  int
+		FrIx int `linepat:"from"`
+		ToIx int `linepat:"to"`
+	}
+	cmd, err := Parse[command](MustCompile("move {int:qty} from {int:from} to {int:to}"), "move 3 from 1 to 2")
+	if err != nil || cmd != (command{3, 1, 2}) {
+		t.Errorf("got %+v %v", cmd, err)
+	}
+
+	// unnamed placeholders go by position
+	type pair struct {
+		Lo, Hi int
+		hidden int
+	}
+	pr, err := Parse[pair](MustCompile("{int}-{int}"), "2-4")
+	if err != nil || pr.Lo != 2 || pr.Hi != 4 {
+		t.Errorf("got %+v %v", pr, err)
+	}
+
+	if _, err := Parse[pair](MustCompile("{int:nope}"), "1"); err == nil {
+		t.Error("unknown field name accepted")
+	}
+	if _, err := Parse[pair](MustCompile("{int} {int} {int}"), "1 2 3"); err == nil {
+		t.Error("too many placeholders for the struct accepted")
+	}
+	if _, err := Parse[pair](MustCompile("{int}-{int}"), "2 to 4"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("mismatch gave %v", err)
+	}
+	var notStruct int
+	if err := MustCompile("{int}").Unmarshal("1", &notStruct); err == nil {
+		t.Error("Unmarshal into an int succeeded")
+	}
+}
+
+func TestInts(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []int
+	}{
+		{"2-4,6-8", []int{2, 4, 6, 8}},
+		{"x=-2, y=18", []int{-2, 18}},
+		{"Sensor at x=2, y=-18: closest beacon is at x=-2, y=15", []int{2, -18, -2, 15}},
+		{"-5 - -3", []int{-5, -3}},
+		{"a-1", []int{-1}},
+		{"no numbers", nil},
+		{"Monkey 3:", []int{3}},
+	}
+	for _, tt := range tests {
+		if got := Ints(tt.s); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Ints(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+	if got := Uints("x=-2, y=18"); !reflect.DeepEqual(got, []int{2, 18}) {
+		t.Errorf("Uints = %v", got)
+	}
+}
@@ -0,0 +1,55 @@
+package pq
+
+// BucketQueue is a min-priority queue for small non-negative integer
+// priorities, such as step counts on a grid where every move costs 1 to 9.
+// Push and Pop are O(1) amortized as long as priorities stay small; there is
+// one bucket per possible priority value.
+//
+// Pop always returns an item with the lowest priority in the queue. When
+// priorities never go below the last popped value (as in Dijkstra with
+// non-negative edge costs) the scan for the next bucket never goes backwards.
+type BucketQueue[T any] struct {
+	buckets [][]T
+	cursor  int
+	count   int
+}
+
+func NewBucketQueue[T any]() *BucketQueue[T] {
+	return &BucketQueue[T]{}
+}
+
+func (q *BucketQueue[T]) Len() int {
+	return q.count
+}
+
+// Push adds v with the given priority, which must not be negative.
+func (q *BucketQueue[T]) Push(v T, priority int) {
+	if priority < 0 {
+		panic("pq: negative priority in BucketQueue")
+	}
+	for priority >= len(q.buckets) {
+		q.buckets = append(q.buckets, nil)
+	}
+	q.buckets[priority] = append(q.buckets[priority], v)
+	if priority < q.cursor {
+		q.cursor = priority
+	}
+	q.count++
+}
+
+// Pop removes and returns an item with the lowest priority, along with that priority.
+func (q *BucketQueue[T]) Pop() (T, int, bool) {
+	var zero T
+	if q.count == 0 {
+		return zero, 0, false
+	}
+	for len(q.buckets[q.cursor]) == 0 {
+		q.cursor++
+	}
+	b := q.buckets[q.cursor]
+	v := b[len(b)-1]
+	b[len(b)-1] = zero
+	q.buckets[q.cursor] = b[:len(b)-1]
+	q.count--
+	return v, q.cursor, true
+}
@@ -0,0 +1,94 @@
+package pq
+
+// Ordered is the set of types that can be compared with <.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Heap is a binary heap of T ordered by a less function; the item for which
+// less is true against every other item is at the top.
+type Heap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func NewHeap[T any](less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{less: less}
+}
+
+// NewMinHeap returns a heap that pops its smallest value first.
+func NewMinHeap[T Ordered]() *Heap[T] {
+	return NewHeap(func(a, b T) bool { return a < b })
+}
+
+// NewMaxHeap returns a heap that pops its largest value first.
+func NewMaxHeap[T Ordered]() *Heap[T] {
+	return NewHeap(func(a, b T) bool { return a > b })
+}
+
+func (h *Heap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *Heap[T]) Push(v T) {
+	h.items = append(h.items, v)
+	h.up(len(h.items) - 1)
+}
+
+// Returns the top item without removing it.
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.items[0], true
+}
+
+// Removes and returns the top item.
+func (h *Heap[T]) Pop() (T, bool) {
+	var zero T
+	n := len(h.items)
+	if n == 0 {
+		return zero, false
+	}
+	top := h.items[0]
+	h.items[0] = h.items[n-1]
+	h.items[n-1] = zero
+	h.items = h.items[:n-1]
+	if len(h.items) > 0 {
+		h.down(0)
+	}
+	return top, true
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.items[i], h.items[parent]) {
+			return
+		}
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	n := len(h.items)
+	for {
+		best := i
+		l, r := 2*i+1, 2*i+2
+		if l < n && h.less(h.items[l], h.items[best]) {
+			best = l
+		}
+		if r < n && h.less(h.items[r], h.items[best]) {
+			best = r
+		}
+		if best == i {
+			return
+		}
+		h.items[i], h.items[best] = h.items[best], h.items[i]
+		i = best
+	}
+}
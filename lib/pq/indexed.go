@@ -0,0 +1,161 @@
+package pq
+
+type entry[K comparable, P any] struct {
+	Key      K
+	Priority P
+}
+
+// IndexedHeap is a priority queue of unique keys that remembers where each key
+// lives in the heap, so that a key's priority can be changed in place. This is
+// the decrease-key operation that Dijkstra and A* want.
+type IndexedHeap[K comparable, P any] struct {
+	entries []entry[K, P]
+	index   map[K]int
+	less    func(a, b P) bool
+}
+
+func NewIndexedHeap[K comparable, P any](less func(a, b P) bool) *IndexedHeap[K, P] {
+	return &IndexedHeap[K, P]{
+		index: make(map[K]int),
+		less:  less,
+	}
+}
+
+// NewIndexedMinHeap returns an indexed heap that pops the lowest priority first.
+func NewIndexedMinHeap[K comparable, P Ordered]() *IndexedHeap[K, P] {
+	return NewIndexedHeap[K](func(a, b P) bool { return a < b })
+}
+
+// NewIndexedMaxHeap returns an indexed heap that pops the highest priority first.
+func NewIndexedMaxHeap[K comparable, P Ordered]() *IndexedHeap[K, P] {
+	return NewIndexedHeap[K](func(a, b P) bool { return a > b })
+}
+
+func (h *IndexedHeap[K, P]) Len() int {
+	return len(h.entries)
+}
+
+func (h *IndexedHeap[K, P]) Contains(key K) bool {
+	_, ok := h.index[key]
+	return ok
+}
+
+// Returns the current priority of key, if it is in the heap.
+func (h *IndexedHeap[K, P]) Priority(key K) (P, bool) {
+	if i, ok := h.index[key]; ok {
+		return h.entries[i].Priority, true
+	}
+	var zero P
+	return zero, false
+}
+
+// Set adds key with the given priority, or changes its priority if it is
+// already present. The priority may move in either direction.
+func (h *IndexedHeap[K, P]) Set(key K, priority P) {
+	if i, ok := h.index[key]; ok {
+		old := h.entries[i].Priority
+		h.entries[i].Priority = priority
+		if h.less(priority, old) {
+			h.up(i)
+		} else {
+			h.down(i)
+		}
+		return
+	}
+	h.entries = append(h.entries, entry[K, P]{Key: key, Priority: priority})
+	i := len(h.entries) - 1
+	h.index[key] = i
+	h.up(i)
+}
+
+// Improve sets the priority of key only if it is new or the new priority is
+// better than the one already stored, and returns true if it changed anything.
+// This is the usual "relax an edge" step.
+func (h *IndexedHeap[K, P]) Improve(key K, priority P) bool {
+	if i, ok := h.index[key]; ok && !h.less(priority, h.entries[i].Priority) {
+		return false
+	}
+	h.Set(key, priority)
+	return true
+}
+
+func (h *IndexedHeap[K, P]) Peek() (K, P, bool) {
+	if len(h.entries) == 0 {
+		var k K
+		var p P
+		return k, p, false
+	}
+	return h.entries[0].Key, h.entries[0].Priority, true
+}
+
+func (h *IndexedHeap[K, P]) Pop() (K, P, bool) {
+	if len(h.entries) == 0 {
+		var k K
+		var p P
+		return k, p, false
+	}
+	top := h.entries[0]
+	h.removeAt(0)
+	return top.Key, top.Priority, true
+}
+
+// Removes key from the heap; returns false if it wasn't there.
+func (h *IndexedHeap[K, P]) Remove(key K) bool {
+	i, ok := h.index[key]
+	if !ok {
+		return false
+	}
+	h.removeAt(i)
+	return true
+}
+
+func (h *IndexedHeap[K, P]) removeAt(i int) {
+	last := len(h.entries) - 1
+	delete(h.index, h.entries[i].Key)
+	if i != last {
+		h.entries[i] = h.entries[last]
+		h.index[h.entries[i].Key] = i
+	}
+	h.entries[last] = entry[K, P]{}
+	h.entries = h.entries[:last]
+	if i < len(h.entries) {
+		h.down(i)
+		h.up(i)
+	}
+}
+
+func (h *IndexedHeap[K, P]) swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.index[h.entries[i].Key] = i
+	h.index[h.entries[j].Key] = j
+}
+
+func (h *IndexedHeap[K, P]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.entries[i].Priority, h.entries[parent].Priority) {
+			return
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+func (h *IndexedHeap[K, P]) down(i int) {
+	n := len(h.entries)
+	for {
+		best := i
+		l, r := 2*i+1, 2*i+2
+		if l < n && h.less(h.entries[l].Priority, h.entries[best].Priority) {
+			best = l
+		}
+		if r < n && h.less(h.entries[r].Priority, h.entries[best].Priority) {
+			best = r
+		}
+		if best == i {
+			return
+		}
+		h.swap(i, best)
+		i = best
+	}
+}
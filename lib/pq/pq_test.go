@@ -0,0 +1,248 @@
+package pq
+
+import (
+	"container/heap"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestHeapOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewMinHeap[int]()
+	var want []int
+	for i := 0; i < 1000; i++ {
+		v := r.Intn(100)
+		h.Push(v)
+		want = append(want, v)
+	}
+	sort.Ints(want)
+	for i, w := range want {
+		v, ok := h.Pop()
+		if !ok || v != w {
+			t.Fatalf("pop %d: got %d %v, want %d", i, v, ok, w)
+		}
+	}
+	if _, ok := h.Pop(); ok {
+		t.Error("pop from empty heap succeeded")
+	}
+}
+
+// pops everything and returns the keys in order
+func drain(h *IndexedHeap[string, int]) []string {
+	var keys []string
+	for h.Len() > 0 {
+		k, _, _ := h.Pop()
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func sameKeys(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func fill() *IndexedHeap[string, int] {
+	h := NewIndexedMinHeap[string, int]()
+	for i, k := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		h.Set(k, (i+1)*10)
+	}
+	return h
+}
+
+func TestIndexedHeapSet(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		pri  int
+		want []string
+	}{
+		{"decrease to top", "f", 5, []string{"f", "a", "b", "c", "d", "e", "g"}},
+		{"decrease in middle", "g", 25, []string{"a", "b", "g", "c", "d", "e", "f"}},
+		{"increase to bottom", "a", 100, []string{"b", "c", "d", "e", "f", "g", "a"}},
+		{"increase in middle", "b", 45, []string{"a", "c", "d", "b", "e", "f", "g"}},
+		{"unchanged", "d", 40, []string{"a", "b", "c", "d", "e", "f", "g"}},
+		{"new key", "h", 35, []string{"a", "b", "c", "h", "d", "e", "f", "g"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := fill()
+			h.Set(tt.key, tt.pri)
+			if p, ok := h.Priority(tt.key); !ok || p != tt.pri {
+				t.Errorf("Priority(%s) = %d %v, want %d", tt.key, p, ok, tt.pri)
+			}
+			sameKeys(t, drain(h), tt.want)
+		})
+	}
+}
+
+func TestIndexedHeapImprove(t *testing.T) {
+	h := fill()
+	if h.Improve("c", 50) {
+		t.Error("Improve with a worse priority changed the heap")
+	}
+	if h.Improve("c", 30) {
+		t.Error("Improve with the same priority changed the heap")
+	}
+	if !h.Improve("c", 1) {
+		t.Error("Improve with a better priority didn't change the heap")
+	}
+	if !h.Improve("z", 1000) {
+		t.Error("Improve didn't add a new key")
+	}
+	sameKeys(t, drain(h), []string{"c", "a", "b", "d", "e", "f", "g", "z"})
+
+	mh := NewIndexedMaxHeap[string, int]()
+	mh.Set("x", 5)
+	if mh.Improve("x", 3) || !mh.Improve("x", 8) {
+		t.Error("Improve on a max heap should only accept higher priorities")
+	}
+}
+
+func TestIndexedHeapRemove(t *testing.T) {
+	for _, k := range []string{"a", "c", "d", "g"} {
+		h := fill()
+		if !h.Remove(k) {
+			t.Fatalf("Remove(%s) failed", k)
+		}
+		if h.Contains(k) || h.Remove(k) {
+			t.Fatalf("%s still in heap after Remove", k)
+		}
+		var want []string
+		for _, w := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+			if w != k {
+				want = append(want, w)
+			}
+		}
+		sameKeys(t, drain(h), want)
+	}
+
+	// removing from the middle can require moving the replacement up
+	h := NewIndexedMinHeap[string, int]()
+	for i, k := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		h.Set(k, []int{1, 10, 2, 11, 12, 3, 4}[i])
+	}
+	h.Remove("e")
+	sameKeys(t, drain(h), []string{"a", "c", "f", "g", "b", "d"})
+}
+
+func TestBucketQueue(t *testing.T) {
+	q := NewBucketQueue[string]()
+	q.Push("five", 5)
+	q.Push("three", 3)
+	if v, p, _ := q.Pop(); v != "three" || p != 3 {
+		t.Fatalf("got %s %d, want three 3", v, p)
+	}
+	// below the cursor, which has moved up to 3
+	q.Push("one", 1)
+	q.Push("four", 4)
+	for _, want := range []struct {
+		v string
+		p int
+	}{{"one", 1}, {"four", 4}, {"five", 5}} {
+		v, p, ok := q.Pop()
+		if !ok || v != want.v || p != want.p {
+			t.Fatalf("got %s %d %v, want %s %d", v, p, ok, want.v, want.p)
+		}
+	}
+	if _, _, ok := q.Pop(); ok || q.Len() != 0 {
+		t.Error("queue should be empty")
+	}
+}
+
+func TestBucketQueueNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("negative priority didn't panic")
+		}
+	}()
+	NewBucketQueue[int]().Push(1, -1)
+}
+
+// benchmarks simulate a Dijkstra-like workload: pop the best item and push a
+// few slightly worse ones, with small priorities
+
+const benchSize = 10000
+
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+func costs() []int {
+	r := rand.New(rand.NewSource(1))
+	c := make([]int, benchSize*2)
+	for i := range c {
+		c[i] = 1 + r.Intn(9)
+	}
+	return c
+}
+
+func BenchmarkContainerHeap(b *testing.B) {
+	c := costs()
+	for i := 0; i < b.N; i++ {
+		h := &intHeap{0}
+		for n := 0; n < benchSize; n++ {
+			p := heap.Pop(h).(int)
+			heap.Push(h, p+c[2*n])
+			heap.Push(h, p+c[2*n+1])
+		}
+	}
+}
+
+func BenchmarkHeap(b *testing.B) {
+	c := costs()
+	for i := 0; i < b.N; i++ {
+		h := NewMinHeap[int]()
+		h.Push(0)
+		for n := 0; n < benchSize; n++ {
+			p, _ := h.Pop()
+			h.Push(p + c[2*n])
+			h.Push(p + c[2*n+1])
+		}
+	}
+}
+
+func BenchmarkIndexedHeap(b *testing.B) {
+	c := costs()
+	for i := 0; i < b.N; i++ {
+		h := NewIndexedMinHeap[int, int]()
+		h.Set(0, 0)
+		key := 1
+		for n := 0; n < benchSize; n++ {
+			_, p, _ := h.Pop()
+			h.Set(key, p+c[2*n])
+			h.Set(key+1, p+c[2*n+1])
+			key += 2
+		}
+	}
+}
+
+func BenchmarkBucketQueue(b *testing.B) {
+	c := costs()
+	for i := 0; i < b.N; i++ {
+		q := NewBucketQueue[int]()
+		q.Push(0, 0)
+		for n := 0; n < benchSize; n++ {
+			v, p, _ := q.Pop()
+			q.Push(v, p+c[2*n])
+			q.Push(v, p+c[2*n+1])
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package search is a memoized depth-first branch-and-bound search for puzzles
+// of the "what's the most you can get in N minutes" kind (days 16 and 19).
+//
+// The caller describes the puzzle as a Problem: how to generate the moves
+// available from a state, a key that identifies everything about a state that
+// affects its future, and an optimistic bound on how much more score a state
+// could possibly earn. Each call to Maximize gets its own cache, so nothing
+// leaks between searches.
+package search
+
+// Move is one way to leave a state: the action taken, the state it leads to,
+// and the score earned by taking it.
+type Move[S any, A any] struct {
+	Action A
+	State  S
+	Reward int
+}
+
+// Problem describes a search space.
+//
+// Key must capture everything that affects the moves and rewards available from
+// a state (for day 16, the current valve, the set of open valves and the time
+// remaining) but not the score earned so far, and the state graph must not
+// contain cycles; including the time in the key is the usual way to ensure that.
+//
+// Bound must never be less than the best additional score obtainable from s.
+// The closer it is, the more of the tree gets pruned; a state with no moves
+// has a best additional score of zero.
+type Problem[S any, A any, K comparable] interface {
+	Key(s S) K
+	Successors(s S) []Move[S, A]
+	Bound(s S) int
+}
+
+// Funcs adapts a set of functions to the Problem interface.
+type Funcs[S any, A any, K comparable] struct {
+	KeyFunc        func(s S) K
+	SuccessorsFunc func(s S) []Move[S, A]
+	BoundFunc      func(s S) int
+}
+
+func (f Funcs[S, A, K]) Key(s S) K                   { return f.KeyFunc(s) }
+func (f Funcs[S, A, K]) Successors(s S) []Move[S, A] { return f.SuccessorsFunc(s) }
+func (f Funcs[S, A, K]) Bound(s S) int               { return f.BoundFunc(s) }
+
+// Stats counts what happened during a search.
+type Stats struct {
+	Expanded    int // states whose successors were generated
+	Leaves      int // states with no successors
+	CacheHits   int // states answered exactly from the cache
+	CachePrunes int // states skipped because a cached upper bound couldn't win
+	BoundPrunes int // states skipped because Bound couldn't win
+}
+
+// Result is the best score found and the actions that achieve it.
+type Result[A any] struct {
+	Score   int
+	Actions []A
+	Stats   Stats
+}
+
+// what we know about the future of a state: the best score actually found
+// (with the path to it) and an upper bound on what's possible. When the two
+// are equal the entry is exact.
+type entry[A any] struct {
+	found  bool
+	value  int
+	upper  int
+	action A
+	next   *entry[A]
+	moved  bool
+}
+
+func (e *entry[A]) exact() bool {
+	return e.found && e.value == e.upper
+}
+
+type searcher[S any, A any, K comparable] struct {
+	problem Problem[S, A, K]
+	cache   map[K]*entry[A]
+	found   bool
+	best    int
+	stats   Stats
+}
+
+// Maximize searches from start for the sequence of moves with the greatest
+// total reward.
+func Maximize[S any, A any, K comparable](p Problem[S, A, K], start S) Result[A] {
+	s := &searcher[S, A, K]{
+		problem: p,
+		cache:   make(map[K]*entry[A]),
+	}
+	e := s.visit(start, 0)
+	var actions []A
+	for n := e; n != nil && n.moved; n = n.next {
+		actions = append(actions, n.action)
+	}
+	return Result[A]{Score: e.value, Actions: actions, Stats: s.stats}
+}
+
+// visit returns what is known about the future of state, which was reached
+// with a score of earned.
+func (s *searcher[S, A, K]) visit(state S, earned int) *entry[A] {
+	key := s.problem.Key(state)
+	if e, ok := s.cache[key]; ok {
+		if e.exact() {
+			s.stats.CacheHits++
+			s.improve(earned + e.value)
+			return e
+		}
+		if s.hopeless(earned + e.upper) {
+			s.stats.CachePrunes++
+			return e
+		}
+	}
+
+	bound := s.problem.Bound(state)
+	if s.hopeless(earned + bound) {
+		s.stats.BoundPrunes++
+		e := &entry[A]{upper: bound}
+		s.remember(key, e)
+		return e
+	}
+
+	moves := s.problem.Successors(state)
+	if len(moves) == 0 {
+		s.stats.Leaves++
+		s.improve(earned)
+		e := &entry[A]{found: true}
+		s.cache[key] = e
+		return e
+	}
+
+	s.stats.Expanded++
+	e := &entry[A]{}
+	for i, m := range moves {
+		child := s.visit(m.State, earned+m.Reward)
+		if child.found && (!e.found || m.Reward+child.value > e.value) {
+			e.found = true
+			e.value = m.Reward + child.value
+			e.action = m.Action
+			e.next = child
+			e.moved = true
+		}
+		if i == 0 || m.Reward+child.upper > e.upper {
+			e.upper = m.Reward + child.upper
+		}
+	}
+	if e.upper > bound {
+		e.upper = bound
+	}
+	s.remember(key, e)
+	return e
+}
+
+// true if a path that can score at most score can't beat what we already have
+func (s *searcher[S, A, K]) hopeless(score int) bool {
+	return s.found && score <= s.best
+}
+
+// keeps the more informative of two entries for the same state
+func (s *searcher[S, A, K]) remember(key K, e *entry[A]) {
+	if old, ok := s.cache[key]; ok && !e.exact() && old.found && (!e.found || old.value >= e.value) && old.upper <= e.upper {
+		return
+	}
+	s.cache[key] = e
+}
+
+func (s *searcher[S, A, K]) improve(score int) {
+	if !s.found || score > s.best {
+		s.found = true
+		s.best = score
+	}
+}
@@ -0,0 +1,146 @@
+package search
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type edge struct {
+	to     int
+	reward int
+}
+
+// a random DAG: edges only go from lower to higher nodes, so there are no
+// cycles, and several paths usually lead to each node so the cache matters.
+// There is at most one edge between two nodes, so a list of actions (the
+// nodes visited) is a unique path.
+type dag struct {
+	edges [][]edge
+	best  []int // the exact best score from each node
+	slack []int // added to best to make a looser bound
+}
+
+func randomDAG(r *rand.Rand, n int) *dag {
+	d := &dag{edges: make([][]edge, n), best: make([]int, n), slack: make([]int, n)}
+	for i := 0; i < n-1; i++ {
+		seen := map[int]bool{}
+		for k := r.Intn(4); k > 0; k-- {
+			to := i + 1 + r.Intn(n-i-1)
+			if !seen[to] {
+				seen[to] = true
+				d.edges[i] = append(d.edges[i], edge{to: to, reward: r.Intn(20) - 5})
+			}
+		}
+		d.slack[i] = r.Intn(15)
+	}
+	d.bruteForce()
+	return d
+}
+
+// works out the best score from every node, from the last node back
+func (d *dag) bruteForce() {
+	d.best = make([]int, len(d.edges))
+	for i := len(d.edges) - 1; i >= 0; i-- {
+		for j, e := range d.edges[i] {
+			if v := e.reward + d.best[e.to]; j == 0 || v > d.best[i] {
+				d.best[i] = v
+			}
+		}
+	}
+}
+
+func (d *dag) problem(loose bool) Problem[int, int, int] {
+	return Funcs[int, int, int]{
+		KeyFunc: func(s int) int { return s },
+		SuccessorsFunc: func(s int) []Move[int, int] {
+			var moves []Move[int, int]
+			for _, e := range d.edges[s] {
+				moves = append(moves, Move[int, int]{Action: e.to, State: e.to, Reward: e.reward})
+			}
+			return moves
+		},
+		BoundFunc: func(s int) int {
+			if loose {
+				return d.best[s] + d.slack[s]
+			}
+			return d.best[s]
+		},
+	}
+}
+
+// replay follows the actions from node 0 and returns the total reward, or
+// false if they aren't a path to a node with no moves
+func (d *dag) replay(actions []int) (int, bool) {
+	node, total := 0, 0
+	for _, a := range actions {
+		ok := false
+		for _, e := range d.edges[node] {
+			if e.to == a {
+				total += e.reward
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return 0, false
+		}
+		node = a
+	}
+	return total, len(d.edges[node]) == 0
+}
+
+func TestMaximizeBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var totals [2]Stats
+	for i := 0; i < 500; i++ {
+		d := randomDAG(r, 2+r.Intn(30))
+		for li, loose := range []bool{false, true} {
+			res := Maximize(d.problem(loose), 0)
+			if res.Score != d.best[0] {
+				t.Fatalf("dag %d loose %v: score %d, want %d", i, loose, res.Score, d.best[0])
+			}
+			got, ok := d.replay(res.Actions)
+			if !ok || got != res.Score {
+				t.Fatalf("dag %d loose %v: actions %v score %d %v, want %d", i, loose, res.Actions, got, ok, res.Score)
+			}
+			st := res.Stats
+			totals[li].Expanded += st.Expanded
+			totals[li].Leaves += st.Leaves
+			totals[li].CacheHits += st.CacheHits
+			totals[li].CachePrunes += st.CachePrunes
+			totals[li].BoundPrunes += st.BoundPrunes
+		}
+	}
+	for li, st := range totals {
+		if st.Expanded == 0 || st.Leaves == 0 || st.CacheHits == 0 || st.BoundPrunes == 0 {
+			t.Errorf("bound %d: some counters never moved: %+v", li, st)
+		}
+	}
+	if totals[1].CachePrunes == 0 {
+		t.Errorf("loose bound never pruned from the cache: %+v", totals[1])
+	}
+}
+
+func TestMaximizeNegative(t *testing.T) {
+	// every path loses; the best is the one that loses least
+	d := &dag{edges: [][]edge{
+		{{1, -5}, {2, -3}},
+		{{3, -1}},
+		{{3, -4}},
+		nil,
+	}}
+	d.bruteForce()
+	d.slack = make([]int, 4)
+	res := Maximize(d.problem(false), 0)
+	if res.Score != -6 || len(res.Actions) != 2 || res.Actions[0] != 1 {
+		t.Errorf("got %d %v, want -6 [1 3]", res.Score, res.Actions)
+	}
+}
+
+func TestMaximizeNoMoves(t *testing.T) {
+	d := &dag{edges: [][]edge{nil}, best: []int{0}, slack: []int{0}}
+	res := Maximize(d.problem(false), 0)
+	if res.Score != 0 || len(res.Actions) != 0 || res.Stats.Leaves != 1 {
+		t.Errorf("got %+v", res)
+	}
+}
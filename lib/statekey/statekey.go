@@ -0,0 +1,151 @@
+// Package statekey builds canonical byte keys for simulation states and hashes
+// them with wyhash, replacing keys put together by hand (day 17) or from
+// fmt.Sprintf of a map (day 19, where the map's random iteration order means
+// the same state can hash differently).
+//
+// A Builder appends values in a fixed encoding: integers as varints, slices
+// and strings with their length first, maps sorted by key. Equal states
+// therefore give equal bytes, and different states give different bytes, so
+// the full key can be kept to rule out hash collisions. A Builder can be Reset
+// and reused, after which building a key and hashing it doesn't allocate.
+package statekey
+
+import (
+	"encoding/binary"
+
+	"github.com/dgryski/go-wyhash"
+)
+
+// DefaultSeed is the wyhash seed used by a Builder with no Seed set.
+const DefaultSeed = 0x14534fe78bc
+
+type Builder struct {
+	Seed uint64
+	buf  []byte
+}
+
+// Reset empties the builder, keeping its buffer.
+func (b *Builder) Reset() {
+	b.buf = b.buf[:0]
+}
+
+func (b *Builder) Int(v int) {
+	b.buf = binary.AppendVarint(b.buf, int64(v))
+}
+
+func (b *Builder) Int64(v int64) {
+	b.buf = binary.AppendVarint(b.buf, v)
+}
+
+func (b *Builder) Uint64(v uint64) {
+	b.buf = binary.AppendUvarint(b.buf, v)
+}
+
+func (b *Builder) Byte(v byte) {
+	b.buf = append(b.buf, v)
+}
+
+func (b *Builder) Bool(v bool) {
+	if v {
+		b.buf = append(b.buf, 1)
+	} else {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// Ints appends the length of a and then its elements.
+func (b *Builder) Ints(a []int) {
+	b.Int(len(a))
+	for _, v := range a {
+		b.Int(v)
+	}
+}
+
+// Bytes appends the length of a and then its contents.
+func (b *Builder) Bytes(a []byte) {
+	b.Int(len(a))
+	b.buf = append(b.buf, a...)
+}
+
+// String appends the length of s and then its contents.
+func (b *Builder) String(s string) {
+	b.Int(len(s))
+	b.buf = append(b.buf, s...)
+}
+
+// Hash returns the wyhash of the key built so far.
+func (b *Builder) Hash() uint64 {
+	seed := b.Seed
+	if seed == 0 {
+		seed = DefaultSeed
+	}
+	return wyhash.Hash(b.buf, seed)
+}
+
+// Key returns a copy of the full key, suitable for comparing or storing.
+func (b *Builder) Key() string {
+	return string(b.buf)
+}
+
+// Equal reports whether the key built so far is the same as key. It doesn't
+// allocate.
+func (b *Builder) Equal(key string) bool {
+	return string(b.buf) == key
+}
+
+// Len returns the length of the key in bytes.
+func (b *Builder) Len() int {
+	return len(b.buf)
+}
+
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// IntSlice appends any kind of integer slice, such as a [4]uint8 of robot
+// counts sliced with [:].
+func IntSlice[T Integer](b *Builder, a []T) {
+	b.Int(len(a))
+	for _, v := range a {
+		b.Int64(int64(v))
+	}
+}
+
+// StringMap appends m in order of its keys. Sorting needs somewhere to put the
+// keys; pass the scratch slice returned by the previous call to avoid
+// allocating a new one each time.
+func StringMap[K ~string, V Integer](b *Builder, m map[K]V, scratch []K) []K {
+	keys := sortedKeys(m, scratch)
+	b.Int(len(keys))
+	for _, k := range keys {
+		b.String(string(k))
+		b.Int64(int64(m[k]))
+	}
+	return keys
+}
+
+// IntMap is StringMap for maps with integer keys.
+func IntMap[K Integer, V Integer](b *Builder, m map[K]V, scratch []K) []K {
+	keys := sortedKeys(m, scratch)
+	b.Int(len(keys))
+	for _, k := range keys {
+		b.Int64(int64(k))
+		b.Int64(int64(m[k]))
+	}
+	return keys
+}
+
+func sortedKeys[K Integer | ~string, V any](m map[K]V, scratch []K) []K {
+	keys := scratch[:0]
+	for k := range m {
+		keys = append(keys, k)
+	}
+	// insertion sort: state maps are small, and sort.Slice would allocate
+	for i := 1; i < len(keys); i++ {
+		for j := i; j > 0 && keys[j] < keys[j-1]; j-- {
+			keys[j], keys[j-1] = keys[j-1], keys[j]
+		}
+	}
+	return keys
+}
@@ -0,0 +1,142 @@
+package statekey
+
+import "testing"
+
+type robots struct {
+	counts [4]uint8
+	stock  map[string]int
+	valves map[int]int
+	time   int
+}
+
+func sample() robots {
+	return robots{
+		counts: [4]uint8{1, 2, 0, 3},
+		stock:  map[string]int{"ore": 4, "clay": 17, "obsidian": 2, "geode": 0},
+		valves: map[int]int{12: 1, 3: 0, 7: 1},
+		time:   19,
+	}
+}
+
+// builds a key the way a solver's hot loop would
+type keyer struct {
+	b       Builder
+	names   []string
+	numbers []int
+}
+
+func (k *keyer) build(r robots) {
+	k.b.Reset()
+	k.b.Int(r.time)
+	IntSlice(&k.b, r.counts[:])
+	k.names = StringMap(&k.b, r.stock, k.names)
+	k.numbers = IntMap(&k.b, r.valves, k.numbers)
+}
+
+func TestDeterministic(t *testing.T) {
+	var k1, k2 keyer
+	r := sample()
+	k1.build(r)
+	// a fresh map with the same contents iterates in a different order
+	r2 := sample()
+	k2.build(r2)
+	if k1.b.Key() != k2.b.Key() || k1.b.Hash() != k2.b.Hash() {
+		t.Error("equal states gave different keys")
+	}
+	r2.stock["clay"]++
+	k2.build(r2)
+	if k1.b.Key() == k2.b.Key() || k1.b.Equal(k2.b.Key()) {
+		t.Error("different states gave the same key")
+	}
+
+	// lengths keep neighbouring fields apart
+	var a, b Builder
+	a.String("ab")
+	a.String("c")
+	b.String("a")
+	b.String("bc")
+	if a.Key() == b.Key() {
+		t.Error("string boundaries are ambiguous")
+	}
+}
+
+func TestNoAllocs(t *testing.T) {
+	var k keyer
+	r := sample()
+	tab := NewTable[int](true)
+	k.build(r)
+	tab.Put(&k.b, 42)
+	var h uint64
+	allocs := testing.AllocsPerRun(1000, func() {
+		k.build(r)
+		h ^= k.b.Hash()
+		if v, ok := tab.Get(&k.b); !ok || v != 42 {
+			t.Fatal("lookup failed")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("building, hashing and looking up a key made %v allocations", allocs)
+	}
+}
+
+func TestTableCollision(t *testing.T) {
+	var a, b Builder
+	a.String("first state")
+	b.String("second state")
+
+	for _, verify := range []bool{true, false} {
+		tab := NewTable[string](verify)
+		tab.Put(&a, "a")
+		// force a into the bucket b hashes to, as if their hashes collided
+		tab.slots[b.Hash()] = tab.slots[a.Hash()]
+		delete(tab.slots, a.Hash())
+
+		v, ok := tab.Get(&b)
+		if !verify {
+			// without the full key the table has to trust the hash
+			if !ok || v != "a" {
+				t.Errorf("unverified lookup got %q %v", v, ok)
+			}
+			continue
+		}
+		if ok || tab.Collisions != 1 {
+			t.Errorf("verified lookup of b got %q %v with %d collisions", v, ok, tab.Collisions)
+		}
+		tab.Put(&b, "b")
+		if tab.Len() != 2 || len(tab.slots[b.Hash()]) != 2 {
+			t.Fatalf("b didn't get its own slot: %d entries", tab.Len())
+		}
+		if v, ok := tab.Get(&b); !ok || v != "b" {
+			t.Errorf("b is %q %v", v, ok)
+		}
+		tab.Put(&b, "b2")
+		if v, _ := tab.Get(&b); v != "b2" || tab.Len() != 2 {
+			t.Errorf("replacing b gave %q and %d entries", v, tab.Len())
+		}
+		if tab.Collisions != 3 {
+			t.Errorf("%d collisions, want 3 (one per lookup of b)", tab.Collisions)
+		}
+	}
+}
+
+func BenchmarkBuildAndHash(b *testing.B) {
+	var k keyer
+	r := sample()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		k.build(r)
+		k.b.Hash()
+	}
+}
+
+func BenchmarkTableGet(b *testing.B) {
+	var k keyer
+	r := sample()
+	tab := NewTable[int](true)
+	k.build(r)
+	tab.Put(&k.b, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tab.Get(&k.b)
+	}
+}
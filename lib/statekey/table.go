@@ -0,0 +1,61 @@
+package statekey
+
+type slot[V any] struct {
+	key   string
+	value V
+}
+
+// Table maps state keys to values, indexed by hash. With Verify set it also
+// stores each full key and compares it on lookup, so two states whose hashes
+// collide are kept apart; without it only the hashes are stored, which is
+// smaller and faster but trusts the hash.
+type Table[V any] struct {
+	Verify     bool
+	Collisions int // lookups that matched a hash but not the full key
+
+	slots map[uint64][]slot[V]
+}
+
+func NewTable[V any](verify bool) *Table[V] {
+	return &Table[V]{
+		Verify: verify,
+		slots:  make(map[uint64][]slot[V]),
+	}
+}
+
+func (t *Table[V]) Len() int {
+	n := 0
+	for _, s := range t.slots {
+		n += len(s)
+	}
+	return n
+}
+
+// Get looks up the key in b.
+func (t *Table[V]) Get(b *Builder) (V, bool) {
+	for _, s := range t.slots[b.Hash()] {
+		if !t.Verify || b.Equal(s.key) {
+			return s.value, true
+		}
+		t.Collisions++
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores v under the key in b, replacing any previous value.
+func (t *Table[V]) Put(b *Builder, v V) {
+	h := b.Hash()
+	slots := t.slots[h]
+	for i := range slots {
+		if !t.Verify || b.Equal(slots[i].key) {
+			slots[i].value = v
+			return
+		}
+	}
+	s := slot[V]{value: v}
+	if t.Verify {
+		s.key = b.Key()
+	}
+	t.slots[h] = append(slots, s)
+}
@@ -0,0 +1,222 @@
+// Package svg writes standalone SVG pictures of puzzle state: grids of
+// coloured cells, paths through them, direction markers, shapes such as day
+// 15's sensor diamonds, and node-and-edge graphs.
+//
+// Everything is drawn in the puzzle's own coordinates. A cell at (x, y) is the
+// unit square from (x, y) to (x+1, y+1), with y increasing downwards as it does
+// when reading input lines, and the picture is scaled to the requested pixel
+// width when it is displayed.
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Point is a position in puzzle coordinates.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Center returns the middle of the cell at x, y.
+func Center(x, y int) Point {
+	return Point{float64(x) + 0.5, float64(y) + 0.5}
+}
+
+// Direction is a facing for Arrow, numbered like day 22's Orientation.
+type Direction int
+
+const (
+	Right Direction = iota
+	Down
+	Left
+	Up
+)
+
+// Style is how a shape is filled and outlined. Colours are any SVG colour
+// string; an empty Fill or Stroke means none.
+type Style struct {
+	Fill        string
+	Stroke      string
+	StrokeWidth float64
+	Opacity     float64 // 0 means fully opaque
+}
+
+func (s Style) attrs() string {
+	var b strings.Builder
+	fill := s.Fill
+	if fill == "" {
+		fill = "none"
+	}
+	fmt.Fprintf(&b, ` fill="%s"`, esc(fill))
+	if s.Stroke != "" {
+		fmt.Fprintf(&b, ` stroke="%s" stroke-width="%s"`, esc(s.Stroke), num(s.StrokeWidth))
+	}
+	if s.Opacity > 0 && s.Opacity < 1 {
+		fmt.Fprintf(&b, ` opacity="%s"`, num(s.Opacity))
+	}
+	return b.String()
+}
+
+// Picture is an SVG document under construction.
+type Picture struct {
+	MinX       float64
+	MinY       float64
+	MaxX       float64
+	MaxY       float64
+	PixelWidth int
+	Background string
+	Title      string
+
+	body bytes.Buffer
+}
+
+// New starts a picture showing the area from minX, minY to maxX, maxY,
+// displayed pixelWidth pixels wide.
+func New(minX, minY, maxX, maxY float64, pixelWidth int) *Picture {
+	return &Picture{
+		MinX:       minX,
+		MinY:       minY,
+		MaxX:       maxX,
+		MaxY:       maxY,
+		PixelWidth: pixelWidth,
+		Background: "white",
+	}
+}
+
+// NewGrid starts a picture of a cols by rows grid with cells cellPixels wide.
+func NewGrid(cols, rows int, cellPixels int) *Picture {
+	return New(0, 0, float64(cols), float64(rows), cols*cellPixels)
+}
+
+// Cell fills the cell at x, y.
+func (p *Picture) Cell(x, y int, fill string) {
+	fmt.Fprintf(&p.body, `<rect x="%d" y="%d" width="1" height="1" fill="%s"/>`+"\n", x, y, esc(fill))
+}
+
+func (p *Picture) Rect(x, y, w, h float64, style Style) {
+	fmt.Fprintf(&p.body, `<rect x="%s" y="%s" width="%s" height="%s"%s/>`+"\n",
+		num(x), num(y), num(w), num(h), style.attrs())
+}
+
+func (p *Picture) Circle(c Point, r float64, style Style) {
+	fmt.Fprintf(&p.body, `<circle cx="%s" cy="%s" r="%s"%s/>`+"\n", num(c.X), num(c.Y), num(r), style.attrs())
+}
+
+func (p *Picture) Line(from, to Point, stroke string, width float64) {
+	fmt.Fprintf(&p.body, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s" stroke-linecap="round"/>`+"\n",
+		num(from.X), num(from.Y), num(to.X), num(to.Y), esc(stroke), num(width))
+}
+
+// Path draws an open line through points, such as a route through cell centres.
+func (p *Picture) Path(points []Point, stroke string, width float64) {
+	if len(points) == 0 {
+		return
+	}
+	fmt.Fprintf(&p.body, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%s" stroke-linejoin="round" stroke-linecap="round"/>`+"\n",
+		pointList(points), esc(stroke), num(width))
+}
+
+// Polygon draws a closed shape.
+func (p *Picture) Polygon(points []Point, style Style) {
+	fmt.Fprintf(&p.body, `<polygon points="%s"%s/>`+"\n", pointList(points), style.attrs())
+}
+
+// Diamond draws the set of points within Manhattan distance r of c.
+func (p *Picture) Diamond(c Point, r float64, style Style) {
+	p.Polygon([]Point{
+		{c.X, c.Y - r},
+		{c.X + r, c.Y},
+		{c.X, c.Y + r},
+		{c.X - r, c.Y},
+	}, style)
+}
+
+// Arrow draws a triangle in the cell at x, y pointing in direction d.
+func (p *Picture) Arrow(x, y int, d Direction, fill string) {
+	c := Center(x, y)
+	// a triangle pointing right, rotated into place
+	tri := []Point{{0.35, 0}, {-0.3, -0.3}, {-0.3, 0.3}}
+	for i, pt := range tri {
+		for n := 0; n < int(d)%4; n++ {
+			pt = Point{-pt.Y, pt.X}
+		}
+		tri[i] = Point{c.X + pt.X, c.Y + pt.Y}
+	}
+	p.Polygon(tri, Style{Fill: fill})
+}
+
+// Text writes s centred on c, size units high.
+func (p *Picture) Text(c Point, s string, size float64, fill string) {
+	fmt.Fprintf(&p.body, `<text x="%s" y="%s" font-size="%s" font-family="monospace" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+		num(c.X), num(c.Y), num(size), esc(fill), esc(s))
+}
+
+// Raw adds an arbitrary SVG element.
+func (p *Picture) Raw(element string) {
+	p.body.WriteString(element)
+	p.body.WriteByte('\n')
+}
+
+// WriteTo writes the finished document to w.
+func (p *Picture) WriteTo(w io.Writer) (int64, error) {
+	width := p.MaxX - p.MinX
+	height := p.MaxY - p.MinY
+	pixelHeight := 0
+	if width > 0 {
+		pixelHeight = int(float64(p.PixelWidth) * height / width)
+	}
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="%s %s %s %s">`+"\n",
+		p.PixelWidth, pixelHeight, num(p.MinX), num(p.MinY), num(width), num(height))
+	if p.Title != "" {
+		fmt.Fprintf(&b, "<title>%s</title>\n", esc(p.Title))
+	}
+	if p.Background != "" {
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+			num(p.MinX), num(p.MinY), num(width), num(height), esc(p.Background))
+	}
+	b.Write(p.body.Bytes())
+	b.WriteString("</svg>\n")
+	return b.WriteTo(w)
+}
+
+// Save writes the document to the named file.
+func (p *Picture) Save(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if _, err := p.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func pointList(points []Point) string {
+	parts := make([]string, len(points))
+	for i, pt := range points {
+		parts[i] = num(pt.X) + "," + num(pt.Y)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formats a coordinate to three decimal places, without trailing zeros
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1000)/1000, 'f', -1, 64)
+}
+
+func esc(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,109 @@
+// Package term draws character grids to a terminal. A Canvas holds the
+// picture, which can be larger than the terminal; a Screen shows part of it,
+// repainting only the cells that changed since the last frame so that
+// animations like day 23's elves don't flicker. When the output isn't a
+// terminal the Screen falls back to plain text with no escape codes.
+package term
+
+import "strings"
+
+// Color is one of the eight standard ANSI colors, or Default.
+type Color uint8
+
+const (
+	Default Color = iota
+	Black
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+)
+
+// Style is how a cell is drawn.
+type Style struct {
+	FG   Color
+	BG   Color
+	Bold bool
+}
+
+// Cell is a single character position.
+type Cell struct {
+	Ch    rune
+	Style Style
+}
+
+var blank = Cell{Ch: ' '}
+
+// Canvas is a fixed-size grid of cells, with (0, 0) at the top left.
+type Canvas struct {
+	Width  int
+	Height int
+	cells  []Cell
+}
+
+func NewCanvas(width, height int) *Canvas {
+	c := &Canvas{
+		Width:  width,
+		Height: height,
+		cells:  make([]Cell, width*height),
+	}
+	c.Clear()
+	return c
+}
+
+func (c *Canvas) InBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Width && y < c.Height
+}
+
+// Get returns the cell at x, y; cells outside the canvas are blank.
+func (c *Canvas) Get(x, y int) Cell {
+	if !c.InBounds(x, y) {
+		return blank
+	}
+	return c.cells[y*c.Width+x]
+}
+
+// Set draws ch at x, y. Drawing outside the canvas is ignored.
+func (c *Canvas) Set(x, y int, ch rune, style Style) {
+	if c.InBounds(x, y) {
+		c.cells[y*c.Width+x] = Cell{Ch: ch, Style: style}
+	}
+}
+
+// Text draws s starting at x, y, clipped at the edge of the canvas.
+func (c *Canvas) Text(x, y int, s string, style Style) {
+	for _, ch := range s {
+		c.Set(x, y, ch, style)
+		x++
+	}
+}
+
+// Fill sets every cell in the rectangle to ch.
+func (c *Canvas) Fill(x0, y0, x1, y1 int, ch rune, style Style) {
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			c.Set(x, y, ch, style)
+		}
+	}
+}
+
+func (c *Canvas) Clear() {
+	for i := range c.cells {
+		c.cells[i] = blank
+	}
+}
+
+// String returns the canvas as plain text, one line per row.
+func (c *Canvas) String() string {
+	var b strings.Builder
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			b.WriteRune(c.Get(x, y).Ch)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package term
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// Screen shows a viewport onto a Canvas. With Color set it positions the
+// cursor and only redraws cells that changed since the previous Draw;
+// otherwise every Draw prints the whole viewport as plain lines of text,
+// which is what you want when output is going to a file or a pipe.
+type Screen struct {
+	Color bool
+
+	// the part of the canvas that is shown
+	ViewX int
+	ViewY int
+	ViewW int
+	ViewH int
+
+	out  *bufio.Writer
+	prev []Cell
+}
+
+// NewScreen returns a screen for f that fills the terminal, less one line at
+// the bottom where Draw leaves the cursor (using the last line would make the
+// terminal scroll on every frame). Color is turned on only if f is a terminal
+// and NO_COLOR isn't set.
+func NewScreen(f *os.File) *Screen {
+	w, h := Size(f)
+	if h > 1 {
+		h--
+	}
+	_, nocolor := os.LookupEnv("NO_COLOR")
+	return NewScreenWriter(f, IsTerminal(f) && !nocolor, w, h)
+}
+
+func NewScreenWriter(w io.Writer, color bool, width, height int) *Screen {
+	return &Screen{
+		Color: color,
+		ViewW: width,
+		ViewH: height,
+		out:   bufio.NewWriter(w),
+	}
+}
+
+// IsTerminal reports whether f is a character device, which is close enough
+// to "a terminal" for our purposes.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Size returns the size of the terminal f is attached to. If it can't be
+// asked (f isn't a terminal, or the system doesn't support it) it falls back
+// to $COLUMNS and $LINES, which the shell doesn't usually export, and then to
+// 80x24.
+func Size(f *os.File) (int, int) {
+	if w, h, ok := winsize(f); ok {
+		return w, h
+	}
+	w, h := 80, 24
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		w = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		h = n
+	}
+	return w, h
+}
+
+// Scroll moves the viewport by dx, dy.
+func (s *Screen) Scroll(dx, dy int) {
+	s.ViewX += dx
+	s.ViewY += dy
+}
+
+// Follow scrolls the viewport as little as possible to keep x, y on screen
+// with at least margin cells around it.
+func (s *Screen) Follow(x, y, margin int) {
+	if x < s.ViewX+margin {
+		s.ViewX = x - margin
+	}
+	if x >= s.ViewX+s.ViewW-margin {
+		s.ViewX = x - s.ViewW + margin + 1
+	}
+	if y < s.ViewY+margin {
+		s.ViewY = y - margin
+	}
+	if y >= s.ViewY+s.ViewH-margin {
+		s.ViewY = y - s.ViewH + margin + 1
+	}
+}
+
+// Invalidate forces the next Draw to repaint every cell.
+func (s *Screen) Invalidate() {
+	s.prev = nil
+}
+
+// Draw shows the visible part of c, then leaves the cursor on the line below
+// it.
+func (s *Screen) Draw(c *Canvas) error {
+	if !s.Color {
+		return s.drawPlain(c)
+	}
+
+	if len(s.prev) != s.ViewW*s.ViewH {
+		s.prev = make([]Cell, s.ViewW*s.ViewH)
+		s.out.WriteString("\x1b[2J")
+	}
+	var cur Style
+	fmt.Fprint(s.out, "\x1b[0m")
+	cursorX, cursorY := -1, -1
+	for y := 0; y < s.ViewH; y++ {
+		for x := 0; x < s.ViewW; x++ {
+			cell := c.Get(s.ViewX+x, s.ViewY+y)
+			ix := y*s.ViewW + x
+			if s.prev[ix] == cell {
+				continue
+			}
+			s.prev[ix] = cell
+			if x != cursorX || y != cursorY {
+				fmt.Fprintf(s.out, "\x1b[%d;%dH", y+1, x+1)
+			}
+			if cell.Style != cur {
+				s.out.WriteString(escape(cell.Style))
+				cur = cell.Style
+			}
+			s.out.WriteRune(cell.Ch)
+			cursorX, cursorY = x+1, y
+		}
+	}
+	fmt.Fprintf(s.out, "\x1b[0m\x1b[%d;1H", s.ViewH+1)
+	return s.out.Flush()
+}
+
+func (s *Screen) drawPlain(c *Canvas) error {
+	for y := 0; y < s.ViewH && s.ViewY+y < c.Height; y++ {
+		for x := 0; x < s.ViewW && s.ViewX+x < c.Width; x++ {
+			s.out.WriteRune(c.Get(s.ViewX+x, s.ViewY+y).Ch)
+		}
+		s.out.WriteByte('\n')
+	}
+	s.out.WriteByte('\n')
+	return s.out.Flush()
+}
+
+// returns the SGR sequence that selects style from a reset state
+func escape(st Style) string {
+	fg, bg := 39, 49
+	if st.FG != Default {
+		fg = 29 + int(st.FG)
+	}
+	if st.BG != Default {
+		bg = 39 + int(st.BG)
+	}
+	bold := ""
+	if st.Bold {
+		bold = "1;"
+	}
+	return fmt.Sprintf("\x1b[0;%s%d;%dm", bold, fg, bg)
+}
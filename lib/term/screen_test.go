@@ -0,0 +1,42 @@
+package term
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSizeFallback(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "notatty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("COLUMNS", "")
+	t.Setenv("LINES", "")
+	if w, h := Size(f); w != 80 || h != 24 {
+		t.Errorf("Size = %dx%d, want 80x24", w, h)
+	}
+	t.Setenv("COLUMNS", "132")
+	t.Setenv("LINES", "50")
+	if w, h := Size(f); w != 132 || h != 50 {
+		t.Errorf("Size = %dx%d, want 132x50", w, h)
+	}
+	if s := NewScreen(f); s.ViewW != 132 || s.ViewH != 49 || s.Color {
+		t.Errorf("NewScreen view is %dx%d color %v, want 132x49 without color", s.ViewW, s.ViewH, s.Color)
+	}
+}
+
+func TestDrawParksCursor(t *testing.T) {
+	var b strings.Builder
+	s := NewScreenWriter(&b, true, 4, 3)
+	c := NewCanvas(4, 3)
+	c.Set(1, 1, '#', Style{})
+	if err := s.Draw(c); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(b.String(), "\x1b[4;1H") {
+		t.Errorf("cursor not left below the view: %q", b.String())
+	}
+}
@@ -1,10 +1,19 @@
 #! /bin/bash
 
+# ./setup.sh DAY        sets up dayDAY at the top level, for 2022
+# ./setup.sh YEAR DAY   sets up YEAR/dayDD, using the shared libraries in lib
+
 if [ -z $1 ]; then
   echo "Day number required"
   exit 1
 fi
 
+if [ -n "$2" ]; then
+  dir=$(cd lib && go run ./cmd/newday -year $1 -day $2) || exit 1
+  code $dir
+  exit 0
+fi
+
 cp -r _template day$1
 cd day$1
 sed s/XXX/day$1/ <../_template/go.mod >go.mod